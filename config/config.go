@@ -0,0 +1,389 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// App holds the configuration loaded at startup, use it after Load has been called
+var App *Config
+
+type Config struct {
+	Server     ServerConfig     `yaml:"server" toml:"server"`
+	Database   DatabaseConfig   `yaml:"database" toml:"database"`
+	JWT        JWTConfig        `yaml:"jwt" toml:"jwt"`
+	Upload     UploadConfig     `yaml:"upload" toml:"upload"`
+	Admin      AdminConfig      `yaml:"admin" toml:"admin"`
+	Password   PasswordConfig   `yaml:"password" toml:"password"`
+	Notifier   NotifierConfig   `yaml:"notifier" toml:"notifier"`
+	Login      LoginConfig      `yaml:"login" toml:"login"`
+	Labor      LaborConfig      `yaml:"labor" toml:"labor"`
+	Attendance AttendanceConfig `yaml:"attendance" toml:"attendance"`
+}
+
+type ServerConfig struct {
+	Port string `yaml:"port" toml:"port"`
+}
+
+type DatabaseConfig struct {
+	Driver string `yaml:"driver" toml:"driver"`
+	DSN    string `yaml:"dsn" toml:"dsn"`
+}
+
+type JWTConfig struct {
+	Secret string `yaml:"secret" toml:"secret"`
+	// TokenTTL is the lifetime of access tokens, RefreshTokenTTL of the refresh tokens used to renew them
+	TokenTTL        Duration `yaml:"token_ttl" toml:"token_ttl"`
+	RefreshTokenTTL Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+}
+
+type UploadConfig struct {
+	Dir       string `yaml:"dir" toml:"dir"`
+	MaxSizeMB int64  `yaml:"max_size_mb" toml:"max_size_mb"`
+}
+
+type AdminConfig struct {
+	// SuperAdminPosition is the position name that receives the super_admin role on startup
+	SuperAdminPosition string `yaml:"super_admin_position" toml:"super_admin_position"`
+	// Email of the account that is activated on registration without waiting for approval
+	Email string `yaml:"email" toml:"email"`
+}
+
+type PasswordConfig struct {
+	ResetTokenTTL Duration `yaml:"reset_token_ttl" toml:"reset_token_ttl"`
+	// ResetURL is put in reset messages as ResetURL?token=..., the raw token is sent when empty
+	ResetURL string `yaml:"reset_url" toml:"reset_url"`
+}
+
+type NotifierConfig struct {
+	Driver   string `yaml:"driver" toml:"driver"`
+	FilePath string `yaml:"file_path" toml:"file_path"`
+}
+
+type LoginConfig struct {
+	// MaxAttempts failed logins for one account (IPMaxAttempts for one ip) within AttemptWindow trigger a lockout
+	MaxAttempts   int      `yaml:"max_attempts" toml:"max_attempts"`
+	IPMaxAttempts int      `yaml:"ip_max_attempts" toml:"ip_max_attempts"`
+	AttemptWindow Duration `yaml:"attempt_window" toml:"attempt_window"`
+	// LockoutBase is doubled for every further lockout, up to LockoutMax
+	LockoutBase Duration `yaml:"lockout_base" toml:"lockout_base"`
+	LockoutMax  Duration `yaml:"lockout_max" toml:"lockout_max"`
+}
+
+// LaborConfig holds the labor rules checked when schedules are planned, a zero value disables a rule
+type LaborConfig struct {
+	// MinRest is the minimum time between the end of a shift and the start of the next one
+	MinRest            Duration `yaml:"min_rest" toml:"min_rest"`
+	MaxWeeklyHours     float64  `yaml:"max_weekly_hours" toml:"max_weekly_hours"`
+	MaxConsecutiveDays int      `yaml:"max_consecutive_days" toml:"max_consecutive_days"`
+}
+
+type AttendanceConfig struct {
+	// ClockDriftLimit is how far the time reported by a device may be from the server time before the
+	// check-in or check-out is flagged, 0 disables the flag
+	ClockDriftLimit Duration `yaml:"clock_drift_limit" toml:"clock_drift_limit"`
+	// MaxLocationAccuracy (meters) is the worst GPS accuracy accepted for the geofence check, 0 accepts any
+	MaxLocationAccuracy float64 `yaml:"max_location_accuracy" toml:"max_location_accuracy"`
+	// KioskCodePeriod is how often the code shown by a kiosk changes
+	KioskCodePeriod Duration `yaml:"kiosk_code_period" toml:"kiosk_code_period"`
+}
+
+// MaxSizeBytes returns the upload limit in bytes
+func (u UploadConfig) MaxSizeBytes() int64 {
+	return u.MaxSizeMB * 1024 * 1024
+}
+
+// Duration wraps time.Duration so it can be written as "24h" or "15m" in config files
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// defaults used for anything not set in the config file or environment
+func defaults() *Config {
+	return &Config{
+		Server: ServerConfig{Port: "3000"},
+		Database: DatabaseConfig{
+			Driver: "mysql",
+			DSN:    "root:@tcp(127.0.0.1:3306)/hotelqu_db?parseTime=true",
+		},
+		JWT: JWTConfig{
+			TokenTTL:        Duration{15 * time.Minute},
+			RefreshTokenTTL: Duration{30 * 24 * time.Hour},
+		},
+		Upload:   UploadConfig{Dir: "./uploads", MaxSizeMB: 2},
+		Password: PasswordConfig{ResetTokenTTL: Duration{30 * time.Minute}},
+		Notifier: NotifierConfig{Driver: "log", FilePath: "./notifications.log"},
+		Login: LoginConfig{
+			MaxAttempts:   5,
+			IPMaxAttempts: 20,
+			AttemptWindow: Duration{15 * time.Minute},
+			LockoutBase:   Duration{time.Minute},
+			LockoutMax:    Duration{time.Hour},
+		},
+		Labor: LaborConfig{
+			MinRest:            Duration{11 * time.Hour},
+			MaxWeeklyHours:     40,
+			MaxConsecutiveDays: 6,
+		},
+		Attendance: AttendanceConfig{
+			ClockDriftLimit:     Duration{5 * time.Minute},
+			MaxLocationAccuracy: 100,
+			KioskCodePeriod:     Duration{30 * time.Second},
+		},
+	}
+}
+
+// Load reads the optional config file (CONFIG_FILE, .yaml/.yml or .toml),
+// applies environment variable overrides, validates the result and stores it in App
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	App = cfg
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".toml":
+		err = toml.Unmarshal(content, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(cfg *Config) error {
+	if v := os.Getenv("PORT"); v != "" {
+		cfg.Server.Port = v
+	}
+	if v := os.Getenv("DB_DRIVER"); v != "" {
+		cfg.Database.Driver = v
+	}
+	if v := os.Getenv("DB_DSN"); v != "" {
+		cfg.Database.DSN = v
+	}
+	if v := os.Getenv("JWT_SECRET"); v != "" {
+		cfg.JWT.Secret = v
+	}
+	if v := os.Getenv("JWT_TOKEN_TTL"); v != "" {
+		if err := cfg.JWT.TokenTTL.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid JWT_TOKEN_TTL %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("JWT_REFRESH_TOKEN_TTL"); v != "" {
+		if err := cfg.JWT.RefreshTokenTTL.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid JWT_REFRESH_TOKEN_TTL %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("UPLOAD_DIR"); v != "" {
+		cfg.Upload.Dir = v
+	}
+	if v := os.Getenv("UPLOAD_MAX_SIZE_MB"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid UPLOAD_MAX_SIZE_MB %q: %w", v, err)
+		}
+		cfg.Upload.MaxSizeMB = size
+	}
+	if v := os.Getenv("SUPER_ADMIN_POSITION"); v != "" {
+		cfg.Admin.SuperAdminPosition = v
+	}
+	if v := os.Getenv("ADMIN_EMAIL"); v != "" {
+		cfg.Admin.Email = v
+	}
+	if v := os.Getenv("PASSWORD_RESET_TOKEN_TTL"); v != "" {
+		if err := cfg.Password.ResetTokenTTL.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid PASSWORD_RESET_TOKEN_TTL %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("PASSWORD_RESET_URL"); v != "" {
+		cfg.Password.ResetURL = v
+	}
+	if v := os.Getenv("NOTIFIER_DRIVER"); v != "" {
+		cfg.Notifier.Driver = v
+	}
+	if v := os.Getenv("NOTIFIER_FILE_PATH"); v != "" {
+		cfg.Notifier.FilePath = v
+	}
+	if v := os.Getenv("LOGIN_MAX_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid LOGIN_MAX_ATTEMPTS %q: %w", v, err)
+		}
+		cfg.Login.MaxAttempts = attempts
+	}
+	if v := os.Getenv("LOGIN_IP_MAX_ATTEMPTS"); v != "" {
+		attempts, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid LOGIN_IP_MAX_ATTEMPTS %q: %w", v, err)
+		}
+		cfg.Login.IPMaxAttempts = attempts
+	}
+	if v := os.Getenv("LOGIN_ATTEMPT_WINDOW"); v != "" {
+		if err := cfg.Login.AttemptWindow.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid LOGIN_ATTEMPT_WINDOW %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("LOGIN_LOCKOUT_BASE"); v != "" {
+		if err := cfg.Login.LockoutBase.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid LOGIN_LOCKOUT_BASE %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("LOGIN_LOCKOUT_MAX"); v != "" {
+		if err := cfg.Login.LockoutMax.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid LOGIN_LOCKOUT_MAX %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("LABOR_MIN_REST"); v != "" {
+		if err := cfg.Labor.MinRest.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid LABOR_MIN_REST %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("LABOR_MAX_WEEKLY_HOURS"); v != "" {
+		hours, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid LABOR_MAX_WEEKLY_HOURS %q: %w", v, err)
+		}
+		cfg.Labor.MaxWeeklyHours = hours
+	}
+	if v := os.Getenv("LABOR_MAX_CONSECUTIVE_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid LABOR_MAX_CONSECUTIVE_DAYS %q: %w", v, err)
+		}
+		cfg.Labor.MaxConsecutiveDays = days
+	}
+	if v := os.Getenv("ATTENDANCE_CLOCK_DRIFT_LIMIT"); v != "" {
+		if err := cfg.Attendance.ClockDriftLimit.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid ATTENDANCE_CLOCK_DRIFT_LIMIT %q: %w", v, err)
+		}
+	}
+	if v := os.Getenv("ATTENDANCE_MAX_LOCATION_ACCURACY"); v != "" {
+		meters, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ATTENDANCE_MAX_LOCATION_ACCURACY %q: %w", v, err)
+		}
+		cfg.Attendance.MaxLocationAccuracy = meters
+	}
+	if v := os.Getenv("ATTENDANCE_KIOSK_CODE_PERIOD"); v != "" {
+		if err := cfg.Attendance.KioskCodePeriod.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid ATTENDANCE_KIOSK_CODE_PERIOD %q: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that every required value is present and usable
+func (c *Config) Validate() error {
+	var errs []error
+
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("server port %q must be a number", c.Server.Port))
+	}
+
+	switch strings.ToLower(c.Database.Driver) {
+	case "mysql", "postgres", "postgresql", "sqlite", "sqlite3":
+	default:
+		errs = append(errs, fmt.Errorf("unsupported database driver %q (use mysql, postgres or sqlite)", c.Database.Driver))
+	}
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database dsn is required (set DB_DSN)"))
+	}
+
+	if c.JWT.Secret == "" {
+		errs = append(errs, errors.New("jwt secret is required (set JWT_SECRET or jwt.secret in the config file)"))
+	}
+	if c.JWT.TokenTTL.Duration <= 0 {
+		errs = append(errs, errors.New("jwt token_ttl must be greater than zero"))
+	}
+	if c.JWT.RefreshTokenTTL.Duration <= c.JWT.TokenTTL.Duration {
+		errs = append(errs, errors.New("jwt refresh_token_ttl must be longer than token_ttl"))
+	}
+
+	if c.Upload.Dir == "" {
+		errs = append(errs, errors.New("upload dir is required"))
+	}
+	if c.Upload.MaxSizeMB <= 0 {
+		errs = append(errs, errors.New("upload max_size_mb must be greater than zero"))
+	}
+
+	if c.Password.ResetTokenTTL.Duration <= 0 {
+		errs = append(errs, errors.New("password reset_token_ttl must be greater than zero"))
+	}
+
+	switch strings.ToLower(c.Notifier.Driver) {
+	case "log":
+	case "file":
+		if c.Notifier.FilePath == "" {
+			errs = append(errs, errors.New("notifier file_path is required for the file driver"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported notifier driver %q (use log or file)", c.Notifier.Driver))
+	}
+
+	if c.Login.MaxAttempts <= 0 || c.Login.IPMaxAttempts <= 0 {
+		errs = append(errs, errors.New("login max_attempts and ip_max_attempts must be greater than zero"))
+	}
+	if c.Login.AttemptWindow.Duration <= 0 {
+		errs = append(errs, errors.New("login attempt_window must be greater than zero"))
+	}
+	if c.Login.LockoutBase.Duration <= 0 || c.Login.LockoutMax.Duration < c.Login.LockoutBase.Duration {
+		errs = append(errs, errors.New("login lockout_base must be greater than zero and not longer than lockout_max"))
+	}
+
+	if c.Labor.MinRest.Duration < 0 || c.Labor.MaxWeeklyHours < 0 || c.Labor.MaxConsecutiveDays < 0 {
+		errs = append(errs, errors.New("labor min_rest, max_weekly_hours and max_consecutive_days cannot be negative"))
+	}
+	if c.Attendance.ClockDriftLimit.Duration < 0 || c.Attendance.MaxLocationAccuracy < 0 {
+		errs = append(errs, errors.New("attendance clock_drift_limit and max_location_accuracy cannot be negative"))
+	}
+	if c.Attendance.KioskCodePeriod.Duration < 5*time.Second {
+		errs = append(errs, errors.New("attendance kiosk_code_period must be at least 5s"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
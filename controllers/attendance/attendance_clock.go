@@ -0,0 +1,45 @@
+package attendance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+)
+
+// checkClientClock validates the time reported by the device (HH:MM) and reports whether it is further from
+// the server time now than the configured drift limit. An empty value is not flagged.
+func checkClientClock(value string, now time.Time) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	reported, err := models.ParseShiftClock(value)
+	if err != nil {
+		return false, err
+	}
+	limit := config.App.Attendance.ClockDriftLimit.Duration
+	if limit == 0 {
+		return false, nil
+	}
+
+	server := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+	drift := reported - server
+	if drift < 0 {
+		drift = -drift
+	}
+	// 23:58 on the device and 00:01 on the server are three minutes apart
+	if drift > 12*time.Hour {
+		drift = 24*time.Hour - drift
+	}
+	return drift > limit, nil
+}
+
+// calculateDuration formats the time between check-in and check-out as "X jam Y menit"
+func calculateDuration(clockIn, clockOut time.Time) string {
+	minutes := int(clockOut.Sub(clockIn).Minutes())
+	if minutes < 0 {
+		minutes = 0
+	}
+	return fmt.Sprintf("%d jam %d menit", minutes/60, minutes%60)
+}
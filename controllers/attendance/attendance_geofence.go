@@ -0,0 +1,188 @@
+package attendance
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PunchLocation tells where a check-in or check-out is made: the position of the device and the code of
+// the kiosk the employee scanned
+type PunchLocation struct {
+	Latitude  *float64 `json:"latitude" form:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" form:"longitude" binding:"omitempty,min=-180,max=180"`
+	// Accuracy is the GPS accuracy in meters reported by the device
+	Accuracy *float64 `json:"accuracy" form:"accuracy" binding:"omitempty,min=0"`
+	// KioskCode is the code shown by a kiosk, it proves the employee is at the kiosk
+	KioskCode string `json:"kiosk_code" form:"kiosk_code" binding:"max=100"`
+}
+
+// geofenceCheck is the outcome of comparing a punch with the work locations of the department
+type geofenceCheck struct {
+	Policy string
+	// Result is one of the models.Geofence values, empty when the policy is off
+	Result     string
+	LocationID *uint
+	// Distance in meters to the matched work location, the nearest one when outside
+	Distance *float64
+	// KioskID is the kiosk whose code was scanned
+	KioskID *uint
+	// kioskUse is saved with the punch, see recordKioskUse
+	kioskUse *models.KioskCodeUse
+	// department of the employee, it also holds the punch rules that are not about the location
+	department models.Department
+	message    string
+}
+
+// checkPunchLocation verifies the kiosk code, when one is sent or the department requires it, and the
+// geofence of the employee's department. A refused punch is answered and ok is false.
+func checkPunchLocation(c *gin.Context, employee models.Employee, position PunchLocation, now time.Time) (geofenceCheck, bool) {
+	var department models.Department
+	if err := models.DB.First(&department, employee.Position.DepartmentId).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check location: " + err.Error(),
+		})
+		return geofenceCheck{}, false
+	}
+
+	var kiosk *models.KioskDevice
+	var kioskUse *models.KioskCodeUse
+	if position.KioskCode != "" {
+		verified, use, message, err := verifyKioskCode(position.KioskCode, employee, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to check kiosk code: " + err.Error(),
+			})
+			return geofenceCheck{}, false
+		}
+		if verified == nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": message,
+			})
+			return geofenceCheck{}, false
+		}
+		kiosk, kioskUse = verified, use
+	} else if department.KioskRequired {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "Scan the code of a kiosk to clock in and out",
+		})
+		return geofenceCheck{}, false
+	}
+
+	check, err := checkGeofence(department, position, kiosk)
+	check.department, check.kioskUse = department, kioskUse
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check location: " + err.Error(),
+		})
+		return geofenceCheck{}, false
+	}
+	if check.rejected() {
+		c.JSON(http.StatusForbidden, check.rejection())
+		return geofenceCheck{}, false
+	}
+	return check, true
+}
+
+// checkGeofence compares the position with the active work locations of the department, or only with
+// its own work location when it has one. A scanned kiosk stands for the work location of the kiosk.
+func checkGeofence(department models.Department, position PunchLocation, kiosk *models.KioskDevice) (geofenceCheck, error) {
+	check := geofenceCheck{Policy: department.GeofencePolicy}
+	if check.Policy == "" {
+		check.Policy = models.GeofencePolicyOff
+	}
+
+	if kiosk != nil {
+		check.KioskID, check.LocationID = &kiosk.ID, kiosk.WorkLocationID
+		if check.Policy == models.GeofencePolicyOff {
+			return check, nil
+		}
+		if department.WorkLocationID != nil && (kiosk.WorkLocationID == nil || *kiosk.WorkLocationID != *department.WorkLocationID) {
+			check.Result, check.message = models.GeofenceOutside, "This kiosk is not at the work location of your department"
+		} else {
+			check.Result = models.GeofenceInside
+		}
+		return check, nil
+	}
+
+	query := models.DB.Where("active = ?", true)
+	if department.WorkLocationID != nil {
+		query = query.Where("id = ?", *department.WorkLocationID)
+	}
+	var locations []models.WorkLocation
+	if err := query.Find(&locations).Error; err != nil {
+		return geofenceCheck{}, err
+	}
+
+	hasPosition := position.Latitude != nil && position.Longitude != nil
+	inside := false
+	if hasPosition {
+		// prefer a location the punch is within, then the nearest one
+		for _, location := range locations {
+			distance := math.Round(utils.DistanceMeters(*position.Latitude, *position.Longitude, location.Latitude, location.Longitude))
+			within := distance <= location.RadiusMeters
+			if check.Distance == nil || (within && !inside) || (within == inside && distance < *check.Distance) {
+				id := location.ID
+				check.LocationID, check.Distance, inside = &id, &distance, within
+			}
+		}
+	}
+	if check.Policy == models.GeofencePolicyOff {
+		return check, nil
+	}
+
+	maxAccuracy := config.App.Attendance.MaxLocationAccuracy
+	switch {
+	case !hasPosition:
+		check.Result, check.message = models.GeofenceUnknown, "Your location is required, turn on location services and try again"
+	case maxAccuracy > 0 && position.Accuracy != nil && *position.Accuracy > maxAccuracy:
+		check.Result, check.message = models.GeofenceUnknown, "Your location is not accurate enough, try again"
+	case len(locations) == 0:
+		check.Result, check.message = models.GeofenceOutside, "No work location is set up for your department"
+	case inside:
+		check.Result = models.GeofenceInside
+	default:
+		check.Result, check.message = models.GeofenceOutside, "You are outside the work location"
+	}
+	return check, nil
+}
+
+// rejected reports whether the department refuses the punch
+func (g geofenceCheck) rejected() bool {
+	return g.Policy == models.GeofencePolicyReject && g.Result != models.GeofenceInside
+}
+
+// rejection is the response body for a refused punch
+func (g geofenceCheck) rejection() gin.H {
+	return gin.H{
+		"error":   true,
+		"message": g.message,
+		"geofence": gin.H{
+			"result":           g.Result,
+			"work_location_id": g.LocationID,
+			"distance":         g.Distance,
+		},
+	}
+}
+
+func formatPunchLocation(latitude, longitude, accuracy, distance *float64, locationID *uint, geofence string, kioskID *uint) gin.H {
+	return gin.H{
+		"latitude":         latitude,
+		"longitude":        longitude,
+		"accuracy":         accuracy,
+		"distance":         distance,
+		"work_location_id": locationID,
+		"geofence":         geofence,
+		"kiosk_id":         kioskID,
+	}
+}
@@ -0,0 +1,90 @@
+package attendance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"gorm.io/gorm"
+)
+
+// errKioskCodeUsed means somebody already punched with the kiosk code
+var errKioskCodeUsed = errors.New("kiosk code already used")
+
+const kioskCodeUsedMessage = "This kiosk code was already used, scan the next code"
+
+// verifyKioskCode checks a code scanned at a kiosk. The code of the current and of the previous period are
+// accepted, each code only once. The returned use has to be saved with recordKioskUse together with the
+// punch. When the code is refused the kiosk is nil and the message tells the employee why.
+func verifyKioskCode(code string, employee models.Employee, now time.Time) (*models.KioskDevice, *models.KioskCodeUse, string, error) {
+	kioskID, counter, err := utils.ParseKioskCode(code)
+	if err != nil {
+		return nil, nil, "Invalid kiosk code", nil
+	}
+
+	var kiosk models.KioskDevice
+	if err := models.DB.Where("id = ? AND active = ?", kioskID, true).First(&kiosk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, "Invalid kiosk code", nil
+		}
+		return nil, nil, "", err
+	}
+	// a rotated secret invalidates the codes signed with the old one
+	if !utils.VerifyKioskCode(code, kiosk.Secret) {
+		return nil, nil, "Invalid kiosk code", nil
+	}
+	if kiosk.DepartmentID != nil && *kiosk.DepartmentID != employee.Position.DepartmentId {
+		return nil, nil, "This kiosk is not for your department", nil
+	}
+
+	current := utils.KioskCounter(now, config.App.Attendance.KioskCodePeriod.Duration)
+	if counter > current || counter < current-1 {
+		return nil, nil, "The kiosk code has expired, scan the code again", nil
+	}
+
+	// a code shared with a colleague must not work for them too
+	used, err := kioskCodeUsed(models.DB, kiosk.ID, counter)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if used {
+		return nil, nil, kioskCodeUsedMessage, nil
+	}
+
+	use := &models.KioskCodeUse{
+		KioskID:    kiosk.ID,
+		Counter:    counter,
+		EmployeeID: employee.Id,
+		UsedAt:     now,
+	}
+	return &kiosk, use, "", nil
+}
+
+// recordKioskUse saves the use of a kiosk code in the transaction of the punch, so a failed punch does not
+// use up the code. errKioskCodeUsed means a concurrent punch used it first.
+func recordKioskUse(tx *gorm.DB, use *models.KioskCodeUse) error {
+	if use == nil {
+		return nil
+	}
+	used, err := kioskCodeUsed(tx, use.KioskID, use.Counter)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errKioskCodeUsed
+	}
+	if err := tx.Create(use).Error; err != nil {
+		// the unique index caught a concurrent punch with the same code
+		return errKioskCodeUsed
+	}
+	// uses older than a day can never match a valid code again
+	return tx.Where("used_at < ?", use.UsedAt.Add(-24*time.Hour)).Delete(&models.KioskCodeUse{}).Error
+}
+
+func kioskCodeUsed(db *gorm.DB, kioskID uint, counter int64) (bool, error) {
+	var count int64
+	err := db.Model(&models.KioskCodeUse{}).Where("kiosk_id = ? AND counter = ?", kioskID, counter).Count(&count).Error
+	return count > 0, err
+}
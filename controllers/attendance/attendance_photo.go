@@ -0,0 +1,127 @@
+package attendance
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bindPunchRequest binds a check-in or check-out. A punch with a selfie is sent as multipart form data,
+// one without can still be sent as JSON.
+func bindPunchRequest(c *gin.Context, request interface{}) error {
+	if c.ContentType() == "multipart/form-data" {
+		return c.ShouldBind(request)
+	}
+	return c.ShouldBindJSON(request)
+}
+
+// savePunchPhoto stores the selfie sent in the photo field as proof of the punch, action is "in" or "out".
+// The path is nil when no selfie was sent and the department does not require one. A refused photo is
+// answered and ok is false.
+func savePunchPhoto(c *gin.Context, employee models.Employee, department models.Department, action string) (*string, bool) {
+	file, err := c.FormFile("photo")
+	if err != nil && err != http.ErrMissingFile && err != http.ErrNotMultipart {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid file upload",
+		})
+		return nil, false
+	}
+	if file == nil {
+		if department.SelfieRequired {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "A selfie is required to clock " + action,
+			})
+			return nil, false
+		}
+		return nil, true
+	}
+
+	path, err := utils.SaveImage(file, "attendance", fmt.Sprintf("%d_%s", employee.Id, action))
+	if err != nil {
+		status, message := errormessage.GetUploadErrorMsg(err)
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": message,
+		})
+		return nil, false
+	}
+	return &path, true
+}
+
+// ServeUpload serves an uploaded photo to a logged-in employee. Attendance selfies are only shown to the
+// employee who took them and to reviewers of their department.
+func ServeUpload(c *gin.Context) {
+	relative := strings.TrimPrefix(path.Clean(c.Param("path")), "/")
+	file := filepath.Join(config.App.Upload.Dir, filepath.FromSlash(relative))
+	if relative == "" || relative == "." || strings.Contains(relative, "..") {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "File not found",
+		})
+		return
+	}
+	if info, err := os.Stat(file); err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "File not found",
+		})
+		return
+	}
+
+	if strings.HasPrefix(relative, "attendance/") {
+		employeeId, _ := c.Get("employeeId")
+		var viewer models.Employee
+		if err := models.DB.Preload("Position.Role.Permissions").First(&viewer, employeeId).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Employee not found",
+			})
+			return
+		}
+
+		urlPath := "/uploads/" + relative
+		var attendance models.Attendance
+		if err := models.DB.Preload("Schedule.Employee.Position").
+			Where("clock_in_photo = ? OR clock_out_photo = ?", urlPath, urlPath).
+			First(&attendance).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error":   true,
+					"message": "File not found",
+				})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   true,
+					"message": "Failed to load attendance: " + err.Error(),
+				})
+			}
+			return
+		}
+
+		owner := attendance.Schedule.Employee
+		reviewer := viewer.Position.Role.HasPermission(models.PermissionAttendanceReview) &&
+			viewer.Position.DepartmentId == owner.Position.DepartmentId
+		if owner.Id != viewer.Id && !reviewer {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "You cannot view this photo",
+			})
+			return
+		}
+	}
+
+	c.File(file)
+}
@@ -0,0 +1,234 @@
+package attendance
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type PhotoReviewInput struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"max=255"`
+}
+
+// ListDepartmentAttendance lets supervisors review the punches of their department on a day (date as
+// DD-MM-YYYY, today by default). with_photo=true only returns punches with a selfie and photo_review
+// filters on the review state.
+func ListDepartmentAttendance(c *gin.Context) {
+	supervisor, ok := loadSupervisor(c)
+	if !ok {
+		return
+	}
+
+	date := utils.Now().Format("2006-01-02")
+	if dateParam := c.Query("date"); dateParam != "" {
+		parsed, err := time.Parse("02-01-2006", dateParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		date = parsed.Format("2006-01-02")
+	}
+
+	query := models.DB.
+		Preload("Schedule.Employee.Position").
+		Preload("Schedule.Shift").
+		Joins("JOIN schedules ON schedules.id = attendances.schedule_id").
+		Joins("JOIN employees ON employees.id = schedules.employee_id").
+		Joins("JOIN positions ON positions.id = employees.position_id").
+		Where("positions.department_id = ? AND attendances.date = ?", supervisor.Position.DepartmentId, date)
+	if c.Query("with_photo") == "true" {
+		query = query.Where("attendances.clock_in_photo IS NOT NULL OR attendances.clock_out_photo IS NOT NULL")
+	}
+	if review := c.Query("photo_review"); review != "" {
+		query = query.Where("attendances.photo_review = ?", review)
+	}
+
+	var attendances []models.Attendance
+	if err := query.Order("attendances.clock_in_at, attendances.id").Find(&attendances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to fetch attendance records: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(attendances))
+	for _, attendance := range attendances {
+		data = append(data, formatReviewAttendance(attendance))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":       false,
+		"message":     "Attendance data retrieved successfully",
+		"date":        date,
+		"attendances": data,
+	})
+}
+
+// ReviewAttendancePhoto lets a supervisor approve or reject the selfies of a punch in their department
+func ReviewAttendancePhoto(c *gin.Context) {
+	supervisor, ok := loadSupervisor(c)
+	if !ok {
+		return
+	}
+
+	var input PhotoReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	var attendance models.Attendance
+	if err := models.DB.Preload("Schedule.Employee.Position").Preload("Schedule.Shift").
+		First(&attendance, c.Param("id")).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Attendance not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to load attendance: " + err.Error(),
+			})
+		}
+		return
+	}
+	employee := attendance.Schedule.Employee
+	if employee.Position.DepartmentId != supervisor.Position.DepartmentId {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You can only review attendance of your department",
+		})
+		return
+	}
+	if employee.Id == supervisor.Id {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot review your own attendance",
+		})
+		return
+	}
+	if attendance.ClockInPhoto == nil && attendance.ClockOutPhoto == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "This attendance has no selfie to review",
+		})
+		return
+	}
+
+	now := utils.Now()
+	attendance.PhotoReview = input.Status
+	attendance.PhotoReviewedBy = &supervisor.Id
+	attendance.PhotoReviewedAt = &now
+	attendance.PhotoReviewNote = input.Note
+	if err := models.DB.Model(&attendance).Updates(map[string]interface{}{
+		"photo_review":      attendance.PhotoReview,
+		"photo_reviewed_by": attendance.PhotoReviewedBy,
+		"photo_reviewed_at": attendance.PhotoReviewedAt,
+		"photo_review_note": attendance.PhotoReviewNote,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to review attendance: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":      false,
+		"message":    "Attendance photo reviewed successfully",
+		"attendance": formatReviewAttendance(attendance),
+	})
+}
+
+// loadSupervisor loads the authenticated employee with the position that decides their department
+func loadSupervisor(c *gin.Context) (models.Employee, bool) {
+	var supervisor models.Employee
+	employeeId, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return supervisor, false
+	}
+	if err := models.DB.Preload("Position").First(&supervisor, employeeId).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return supervisor, false
+	}
+	return supervisor, true
+}
+
+// formatReviewAttendance shows a punch with everything a supervisor needs to judge it
+func formatReviewAttendance(attendance models.Attendance) gin.H {
+	schedule := attendance.Schedule
+	return gin.H{
+		"id": attendance.ID,
+		"employee": gin.H{
+			"id":       schedule.Employee.Id,
+			"name":     schedule.Employee.Name,
+			"position": schedule.Employee.Position.PositionName,
+		},
+		"schedule": gin.H{
+			"id":            schedule.ID,
+			"date_schedule": schedule.DateSchedule,
+			"shift": gin.H{
+				"id":         schedule.Shift.ID,
+				"type":       schedule.Shift.Type,
+				"start_time": schedule.Shift.StartTime,
+				"end_time":   schedule.Shift.EndTime,
+			},
+		},
+		"date":             attendance.Date,
+		"clock_in":         attendance.ClockIn(),
+		"clock_out":        attendance.ClockOut(),
+		"clock_in_at":      attendance.ClockInAt,
+		"clock_out_at":     attendance.ClockOutAt,
+		"clock_in_status":  attendance.ClockInStatus,
+		"clock_out_status": attendance.ClockOutStatus,
+		"clock_in_drift":   attendance.ClockInDrift,
+		"clock_out_drift":  attendance.ClockOutDrift,
+		"clock_in_location": formatPunchLocation(attendance.ClockInLatitude, attendance.ClockInLongitude,
+			attendance.ClockInAccuracy, attendance.ClockInDistance, attendance.ClockInLocationID, attendance.ClockInGeofence,
+			attendance.ClockInKioskID),
+		"clock_out_location": formatPunchLocation(attendance.ClockOutLatitude, attendance.ClockOutLongitude,
+			attendance.ClockOutAccuracy, attendance.ClockOutDistance, attendance.ClockOutLocationID, attendance.ClockOutGeofence,
+			attendance.ClockOutKioskID),
+		"clock_in_photo":    attendance.ClockInPhoto,
+		"clock_out_photo":   attendance.ClockOutPhoto,
+		"photo_review":      attendance.PhotoReview,
+		"photo_reviewed_by": attendance.PhotoReviewedBy,
+		"photo_reviewed_at": attendance.PhotoReviewedAt,
+		"photo_review_note": attendance.PhotoReviewNote,
+	}
+}
@@ -0,0 +1,157 @@
+package attendance
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"gorm.io/gorm"
+)
+
+var (
+	errScheduleNotFound     = errors.New("schedule not found for today")
+	errAllCheckedIn         = errors.New("already checked in to every schedule today")
+	errNoScheduleInProgress = errors.New("no schedule in progress")
+)
+
+// clockInOpensBefore is how long before the shift starts an employee may clock in
+const clockInOpensBefore = time.Hour
+
+// clockInClosesAfter is how long after the shift ends a forgotten check-in can still be made
+const clockInClosesAfter = 30 * time.Minute
+
+// openAttendanceWindow is how long after the check-in an attendance can still be closed
+const openAttendanceWindow = 24 * time.Hour
+
+// schedulesBetween returns the published schedules of the employee from one date to another (YYYY-MM-DD)
+// ordered by date and shift start
+func schedulesBetween(employeeID interface{}, from, to string) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := models.DB.Joins("JOIN shifts ON shifts.id = schedules.shift_id").
+		Preload("Shift").Preload("Employee").Preload("Employee.Position").
+		Where("schedules.employee_id = ? AND schedules.date_schedule BETWEEN ? AND ? AND schedules.draft = ?", employeeID, from, to, false).
+		Order("schedules.date_schedule, shifts.start_time, schedules.id").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// scheduleDate returns the date of the schedule as YYYY-MM-DD
+func scheduleDate(schedule models.Schedule) string {
+	date := schedule.DateSchedule
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	return date
+}
+
+// schedulePeriod returns when the shift of the schedule starts and ends in server time, a shift that ends
+// before it starts ends the next day
+func schedulePeriod(schedule models.Schedule) (time.Time, time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", scheduleDate(schedule), time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return schedule.Shift.Period(day)
+}
+
+// findClockInSchedule picks the schedule to check in to at now. The schedules of yesterday and tomorrow are
+// looked at too, so a night shift can be checked in to after midnight and a shift starting at midnight
+// before it. An explicit scheduleID must be one of them and not have ended. Otherwise the first schedule without attendance
+// that is running (or opens for clock-in within the hour) is used, then the next upcoming one of today,
+// then the last one of today unless it ended more than clockInClosesAfter ago.
+func findClockInSchedule(employeeID interface{}, now time.Time, scheduleID uint) (models.Schedule, error) {
+	today := now.Format("2006-01-02")
+	schedules, err := schedulesBetween(employeeID, now.AddDate(0, 0, -1).Format("2006-01-02"), now.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	if scheduleID != 0 {
+		for _, schedule := range schedules {
+			if schedule.ID != scheduleID {
+				continue
+			}
+			if _, end, err := schedulePeriod(schedule); err == nil && now.After(end.Add(clockInClosesAfter)) {
+				return models.Schedule{}, errNoScheduleInProgress
+			}
+			return schedule, nil
+		}
+		return models.Schedule{}, errScheduleNotFound
+	}
+	if len(schedules) == 0 {
+		return models.Schedule{}, errScheduleNotFound
+	}
+
+	ids := make([]uint, len(schedules))
+	for i, schedule := range schedules {
+		ids[i] = schedule.ID
+	}
+	var attendedIDs []uint
+	if err := models.DB.Model(&models.Attendance{}).Where("schedule_id IN ?", ids).
+		Pluck("schedule_id", &attendedIDs).Error; err != nil {
+		return models.Schedule{}, err
+	}
+	attended := make(map[uint]bool, len(attendedIDs))
+	for _, id := range attendedIDs {
+		attended[id] = true
+	}
+
+	var candidates, ofToday []models.Schedule
+	for _, schedule := range schedules {
+		if attended[schedule.ID] {
+			continue
+		}
+		start, end, err := schedulePeriod(schedule)
+		if err != nil {
+			continue
+		}
+		if !now.Before(start.Add(-clockInOpensBefore)) && now.Before(end) {
+			return schedule, nil
+		}
+		candidates = append(candidates, schedule)
+		if scheduleDate(schedule) == today {
+			ofToday = append(ofToday, schedule)
+		}
+	}
+	if len(ofToday) == 0 {
+		if len(candidates) == 0 {
+			return models.Schedule{}, errAllCheckedIn
+		}
+		return models.Schedule{}, errScheduleNotFound
+	}
+	for _, schedule := range ofToday {
+		if start, _, err := schedulePeriod(schedule); err == nil && start.After(now) {
+			return schedule, nil
+		}
+	}
+	last := ofToday[len(ofToday)-1]
+	if _, end, err := schedulePeriod(last); err == nil && now.After(end.Add(clockInClosesAfter)) {
+		return models.Schedule{}, errNoScheduleInProgress
+	}
+	return last, nil
+}
+
+// findOpenAttendance returns the attendance to check out from: the latest check-in without a check-out
+// within the last day, whatever its date, unless a scheduleID is given. gorm.ErrRecordNotFound means
+// there is no recent check-in at all.
+func findOpenAttendance(employeeID interface{}, now time.Time, scheduleID uint) (models.Attendance, error) {
+	query := models.DB.Joins("JOIN schedules ON schedules.id = attendances.schedule_id").
+		Preload("Schedule").Preload("Schedule.Shift").Preload("Schedule.Employee").Preload("Schedule.Employee.Position").
+		Where("schedules.employee_id = ? AND attendances.clock_in_at >= ?", employeeID, now.Add(-openAttendanceWindow))
+	if scheduleID != 0 {
+		query = query.Where("attendances.schedule_id = ?", scheduleID)
+	}
+	var attendances []models.Attendance
+	if err := query.Order("attendances.clock_in_at DESC, attendances.id DESC").Find(&attendances).Error; err != nil {
+		return models.Attendance{}, err
+	}
+	if len(attendances) == 0 {
+		return models.Attendance{}, gorm.ErrRecordNotFound
+	}
+	for _, attendance := range attendances {
+		if attendance.ClockOutAt == nil {
+			return attendance, nil
+		}
+	}
+	// every check-in is closed, the latest one tells the caller so
+	return attendances[0], nil
+}
@@ -0,0 +1,351 @@
+package attendance
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// setupTestDB connects a fresh SQLite database
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("UPLOAD_DIR", t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	models.ConnectDatabase(cfg.Database)
+}
+
+// setNow makes utils.Now return the given time for the rest of the test
+func setNow(t *testing.T, now time.Time) {
+	t.Helper()
+	utils.Now = func() time.Time { return now }
+	t.Cleanup(func() { utils.Now = time.Now })
+}
+
+// at returns the local time on the given date (YYYY-MM-DD) and clock (HH:MM)
+func at(t *testing.T, date, clock string) time.Time {
+	t.Helper()
+	value, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, time.Local)
+	if err != nil {
+		t.Fatalf("parse %s %s: %v", date, clock, err)
+	}
+	return value
+}
+
+// createEmployee creates an active employee in a new department and returns its id
+func createEmployee(t *testing.T) uint {
+	t.Helper()
+	department := models.Department{DepartmentName: "Front Office"}
+	if err := models.DB.Create(&department).Error; err != nil {
+		t.Fatalf("create department: %v", err)
+	}
+	position := models.Position{DepartmentId: department.Id, PositionName: "Receptionist"}
+	if err := models.DB.Create(&position).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+	employee := models.Employee{PositionId: position.Id, Name: "Staff", Email: "staff@example.com", Password: "-",
+		Phone: "1", Status: models.EmployeeStatusActive}
+	if err := models.DB.Create(&employee).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+	return uint(employee.Id)
+}
+
+// createSchedule publishes a schedule for the employee on date (YYYY-MM-DD) with a shift from start to end
+func createSchedule(t *testing.T, employeeID uint, date, start, end string) models.Schedule {
+	t.Helper()
+	shift := models.Shift{Type: start + "-" + end, StartTime: start, EndTime: end}
+	if err := models.DB.Create(&shift).Error; err != nil {
+		t.Fatalf("create shift: %v", err)
+	}
+	schedule := models.Schedule{EmployeeID: employeeID, ShiftID: shift.ID, CreatedBy: employeeID, DateSchedule: date}
+	if err := models.DB.Create(&schedule).Error; err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	return schedule
+}
+
+// clockIn posts a check-in for the employee and returns the decoded response
+func clockIn(t *testing.T, employeeID uint, body string) (int, map[string]interface{}) {
+	t.Helper()
+	return punch(t, http.MethodPost, CreateAttendance, employeeID, body)
+}
+
+// clockOut puts a check-out for the employee and returns the decoded response
+func clockOut(t *testing.T, employeeID uint, body string) (int, map[string]interface{}) {
+	t.Helper()
+	return punch(t, http.MethodPut, UpdateAttendance, employeeID, body)
+}
+
+func punch(t *testing.T, method string, handler gin.HandlerFunc, employeeID uint, body string) (int, map[string]interface{}) {
+	t.Helper()
+	router := gin.New()
+	router.Handle(method, "/attendance", func(c *gin.Context) {
+		c.Set("employeeId", employeeID)
+	}, handler)
+
+	req := httptest.NewRequest(method, "/attendance", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Attendance map[string]interface{} `json:"attendance"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response %s: %v", w.Body, err)
+	}
+	return w.Code, response.Attendance
+}
+
+func TestCreateAttendanceUsesServerClock(t *testing.T) {
+	cases := []struct {
+		name       string
+		now        string
+		body       string
+		wantStatus string
+		wantDrift  bool
+	}{
+		{"on time", "06:55", `{"clock_in":"06:55"}`, "Tepat Waktu", false},
+		{"late", "07:20", `{"clock_in":"07:20"}`, "Terlambat", false},
+		{"device clock drifts", "07:20", `{"clock_in":"07:40"}`, "Terlambat", true},
+		{"device time 07:00 is ignored", "07:30", `{"clock_in":"07:00"}`, "Terlambat", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupTestDB(t)
+			employeeID := createEmployee(t)
+			createSchedule(t, employeeID, "2026-10-18", "07:00", "15:00")
+			now := at(t, "2026-10-18", tc.now)
+			setNow(t, now)
+
+			code, attendance := clockIn(t, employeeID, tc.body)
+			if code != http.StatusCreated {
+				t.Fatalf("got %d, want 201 (%v)", code, attendance)
+			}
+			if attendance["clock_in_status"] != tc.wantStatus {
+				t.Errorf("clock_in_status = %v, want %s", attendance["clock_in_status"], tc.wantStatus)
+			}
+			if attendance["clock_in_drift"] != tc.wantDrift {
+				t.Errorf("clock_in_drift = %v, want %v", attendance["clock_in_drift"], tc.wantDrift)
+			}
+			if attendance["clock_in"] != tc.now {
+				t.Errorf("clock_in = %v, want the server time %s", attendance["clock_in"], tc.now)
+			}
+			var stored models.Attendance
+			models.DB.First(&stored)
+			if stored.ClockInAt == nil || !stored.ClockInAt.Equal(now) {
+				t.Errorf("clock_in_at = %v, want %v", stored.ClockInAt, now)
+			}
+		})
+	}
+}
+
+func TestFindClockInScheduleAcrossMidnight(t *testing.T) {
+	cases := []struct {
+		name, date, start, end string
+		now, nowDate           string
+		wantErr                error
+	}{
+		{"night shift clocked in after midnight", "2026-10-17", "23:00", "07:00", "00:30", "2026-10-18", nil},
+		{"midnight shift clocked in the evening before", "2026-10-18", "00:00", "08:00", "23:15", "2026-10-17", nil},
+		{"forgotten check-in shortly after the shift", "2026-10-18", "07:00", "15:00", "15:20", "2026-10-18", nil},
+		{"shift ended hours ago", "2026-10-18", "07:00", "15:00", "18:00", "2026-10-18", errNoScheduleInProgress},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupTestDB(t)
+			employeeID := createEmployee(t)
+			schedule := createSchedule(t, employeeID, tc.date, tc.start, tc.end)
+
+			found, err := findClockInSchedule(employeeID, at(t, tc.nowDate, tc.now), 0)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("findClockInSchedule: got %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findClockInSchedule: %v", err)
+			}
+			if found.ID != schedule.ID {
+				t.Errorf("got schedule on %s, want the one on %s", scheduleDate(found), tc.date)
+			}
+		})
+	}
+}
+
+func TestClockOutAfterMidnight(t *testing.T) {
+	cases := []struct {
+		name, now, wantStatus string
+	}{
+		{"before the shift ends", "06:50", "Pulang Lebih Awal"},
+		{"after the shift ends", "07:05", "Tepat Waktu"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupTestDB(t)
+			employeeID := createEmployee(t)
+			schedule := createSchedule(t, employeeID, "2026-10-17", "23:00", "07:00")
+			clockInAt := at(t, "2026-10-17", "22:55")
+			attendance := models.Attendance{ScheduleID: schedule.ID, Date: "2026-10-17", ClockInAt: &clockInAt}
+			if err := models.DB.Create(&attendance).Error; err != nil {
+				t.Fatalf("create attendance: %v", err)
+			}
+
+			now := at(t, "2026-10-18", tc.now)
+			open, err := findOpenAttendance(employeeID, now, 0)
+			if err != nil {
+				t.Fatalf("findOpenAttendance: %v", err)
+			}
+			if open.ID != attendance.ID {
+				t.Fatalf("got attendance %d, want %d", open.ID, attendance.ID)
+			}
+			_, shiftEnd, err := schedulePeriod(open.Schedule)
+			if err != nil {
+				t.Fatalf("schedulePeriod: %v", err)
+			}
+			if status := validateClockOut(now, shiftEnd); status != tc.wantStatus {
+				t.Errorf("clock-out status = %s, want %s", status, tc.wantStatus)
+			}
+		})
+	}
+}
+
+// createKiosk registers an active kiosk usable by every department
+func createKiosk(t *testing.T) models.KioskDevice {
+	t.Helper()
+	kiosk := models.KioskDevice{Name: "Kitchen", TokenHash: "hash", Secret: "kiosk-secret", Active: true}
+	if err := models.DB.Create(&kiosk).Error; err != nil {
+		t.Fatalf("create kiosk: %v", err)
+	}
+	return kiosk
+}
+
+func TestVerifyKioskCode(t *testing.T) {
+	setupTestDB(t)
+	kiosk := createKiosk(t)
+	now := at(t, "2026-10-18", "07:00")
+	current := utils.KioskCounter(now, config.App.Attendance.KioskCodePeriod.Duration)
+	staff := models.Employee{Id: 1}
+	colleague := models.Employee{Id: 2}
+
+	cases := []struct {
+		name     string
+		employee models.Employee
+		code     string
+		wantOK   bool
+	}{
+		{"current code", staff, utils.KioskCode(kiosk.ID, current, kiosk.Secret), true},
+		{"previous code", staff, utils.KioskCode(kiosk.ID, current-1, kiosk.Secret), true},
+		{"expired code", staff, utils.KioskCode(kiosk.ID, current-2, kiosk.Secret), false},
+		{"code from the future", staff, utils.KioskCode(kiosk.ID, current+1, kiosk.Secret), false},
+		{"wrong secret", staff, utils.KioskCode(kiosk.ID, current, "other-secret"), false},
+		{"replayed by the same employee", staff, utils.KioskCode(kiosk.ID, current, kiosk.Secret), false},
+		{"replayed by a colleague", colleague, utils.KioskCode(kiosk.ID, current-1, kiosk.Secret), false},
+	}
+	for _, tc := range cases {
+		verified, use, message, err := verifyKioskCode(tc.code, tc.employee, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if (verified != nil) != tc.wantOK {
+			t.Errorf("%s: accepted = %v, want %v (%s)", tc.name, verified != nil, tc.wantOK, message)
+		}
+		if verified != nil {
+			if err := recordKioskUse(models.DB, use); err != nil {
+				t.Fatalf("%s: record use: %v", tc.name, err)
+			}
+		}
+	}
+}
+
+func TestRefusedPunchKeepsKioskCode(t *testing.T) {
+	setupTestDB(t)
+	employeeID := createEmployee(t)
+	createSchedule(t, employeeID, "2026-10-18", "07:00", "15:00")
+	kiosk := createKiosk(t)
+	now := at(t, "2026-10-18", "06:55")
+	setNow(t, now)
+	code := utils.KioskCode(kiosk.ID, utils.KioskCounter(now, config.App.Attendance.KioskCodePeriod.Duration), kiosk.Secret)
+
+	// the check-in is refused after the code was checked because the selfie is missing
+	models.DB.Model(&models.Department{}).Where("1 = 1").Update("selfie_required", true)
+	if code, _ := clockIn(t, employeeID, `{"kiosk_code":"`+code+`"}`); code != http.StatusBadRequest {
+		t.Fatalf("check-in without selfie: got %d, want 400", code)
+	}
+	models.DB.Model(&models.Department{}).Where("1 = 1").Update("selfie_required", false)
+
+	status, attendance := clockIn(t, employeeID, `{"kiosk_code":"`+code+`"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("check-in with the same code: got %d, want 201 (%v)", status, attendance)
+	}
+	var uses int64
+	models.DB.Model(&models.KioskCodeUse{}).Count(&uses)
+	if uses != 1 {
+		t.Errorf("kiosk code uses = %d, want 1", uses)
+	}
+}
+
+func TestDoubleCheckInIsRejected(t *testing.T) {
+	setupTestDB(t)
+	employeeID := createEmployee(t)
+	schedule := createSchedule(t, employeeID, "2026-10-18", "07:00", "15:00")
+	setNow(t, at(t, "2026-10-18", "06:55"))
+
+	if code, attendance := clockIn(t, employeeID, `{}`); code != http.StatusCreated {
+		t.Fatalf("first check-in: got %d, want 201 (%v)", code, attendance)
+	}
+	if code, _ := clockIn(t, employeeID, `{"schedule_id":`+fmt.Sprint(schedule.ID)+`}`); code != http.StatusConflict {
+		t.Errorf("second check-in: got %d, want 409", code)
+	}
+	// a request passing the check at the same time is stopped by the unique index
+	duplicate := models.Attendance{ScheduleID: schedule.ID, Date: "2026-10-18"}
+	if err := models.DB.Create(&duplicate).Error; err == nil {
+		t.Errorf("a second attendance for the schedule was saved")
+	}
+}
+
+func TestCheckOutClosesAttendanceOnce(t *testing.T) {
+	setupTestDB(t)
+	employeeID := createEmployee(t)
+	createSchedule(t, employeeID, "2026-10-18", "07:00", "15:00")
+	setNow(t, at(t, "2026-10-18", "06:55"))
+	if code, attendance := clockIn(t, employeeID, `{}`); code != http.StatusCreated {
+		t.Fatalf("check-in: got %d, want 201 (%v)", code, attendance)
+	}
+
+	setNow(t, at(t, "2026-10-18", "15:05"))
+	code, attendance := clockOut(t, employeeID, `{}`)
+	if code != http.StatusOK {
+		t.Fatalf("check-out: got %d, want 200 (%v)", code, attendance)
+	}
+	setNow(t, at(t, "2026-10-18", "15:10"))
+	if code, _ := clockOut(t, employeeID, `{}`); code != http.StatusConflict {
+		t.Errorf("second check-out: got %d, want 409", code)
+	}
+	var stored models.Attendance
+	models.DB.First(&stored)
+	if want := at(t, "2026-10-18", "15:05"); stored.ClockOutAt == nil || !stored.ClockOutAt.Equal(want) {
+		t.Errorf("clock_out_at = %v, want the first check-out %v", stored.ClockOutAt, want)
+	}
+}
@@ -1,17 +1,22 @@
 package attendance
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CheckInRequest struct {
-	ClockIn string `json:"clock_in" binding:"required"`
+	// ClockIn is the time shown on the device (HH:MM), only kept for reference. The server time is recorded.
+	ClockIn string `json:"clock_in" form:"clock_in"`
+	// ScheduleID selects one of today's schedules, by default the running or next one is used
+	ScheduleID uint `json:"schedule_id" form:"schedule_id"`
+	PunchLocation
 }
 
 // CreateAttendance handles employee check-in
@@ -26,7 +31,7 @@ func CreateAttendance(c *gin.Context) {
 	}
 
 	var request CheckInRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
+	if err := bindPunchRequest(c, &request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
 			"message": "Invalid request format",
@@ -34,35 +39,91 @@ func CreateAttendance(c *gin.Context) {
 		return
 	}
 
-	currentDate := time.Now().Format("2006-01-02")
-
-	// Find employee's schedule for today
-	var schedule models.Schedule
-	result := models.DB.Where("employee_id = ? AND date_schedule = ?", employeeId, currentDate).
-		Preload("Shift").Preload("Employee").Preload("Employee.Position").First(&schedule)
+	// The server clock decides the check-in time, the device time is only compared with it
+	now := utils.Now()
+	drift, err := checkClientClock(request.ClockIn, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "clock_in must be a time like 07:30",
+		})
+		return
+	}
 
-	if result.Error != nil {
+	// Find the schedule to check in to, an employee may work several shifts a day and a night shift
+	// can be checked in to after midnight
+	schedule, err := findClockInSchedule(employeeId, now, request.ScheduleID)
+	if errors.Is(err, errAllCheckedIn) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "You have already checked in to every schedule today",
+		})
+		return
+	}
+	if errors.Is(err, errNoScheduleInProgress) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "No schedule in progress, your last shift today has already ended",
+		})
+		return
+	}
+	if errors.Is(err, errScheduleNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   true,
 			"message": "Schedule not found for today",
 		})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load schedules: " + err.Error(),
+		})
+		return
+	}
+
+	// The attendance belongs to the day of the schedule, also when the shift crosses midnight
+	currentDate := scheduleDate(schedule)
+	shiftStart, _, err := schedulePeriod(schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to read the shift times: " + err.Error(),
+		})
+		return
+	}
+
+	// No clock-in on approved leave days
+	onLeave, err := models.IsOnLeave(models.DB, schedule.EmployeeID, currentDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check leave: " + err.Error(),
+		})
+		return
+	}
+	if onLeave {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You are on approved leave today",
+		})
+		return
+	}
 
 	// Check if attendance already exists
 	var existingAttendance models.Attendance
-	checkResult := models.DB.Where("schedule_id = ? AND date = ?", schedule.ID, currentDate).First(&existingAttendance)
+	checkResult := models.DB.Where("schedule_id = ?", schedule.ID).First(&existingAttendance)
 
 	if checkResult.Error == nil {
 		c.JSON(http.StatusConflict, gin.H{
 			"error":   true,
-			"message": "You have already checked in today",
+			"message": "You have already checked in to this schedule",
 		})
 		return
 	}
 
 	// Validate clock-in time
-	clockInStatus, isValid := validateClockIn(request.ClockIn, schedule.Shift.StartTime)
+	clockInStatus, isValid := validateClockIn(now, shiftStart)
 	if !isValid {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
@@ -71,14 +132,80 @@ func CreateAttendance(c *gin.Context) {
 		return
 	}
 
+	// Departments check where the employee clocks in with a kiosk code or the geofence
+	geofence, ok := checkPunchLocation(c, schedule.Employee, request.PunchLocation, now)
+	if !ok {
+		return
+	}
+
+	// Work on a holiday is marked so payroll can apply holiday rates
+	holidays, err := models.LoadHolidays(models.DB, currentDate, currentDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check holidays: " + err.Error(),
+		})
+		return
+	}
+	holidayName := holidays.Name(currentDate)
+
+	// Departments may ask for a selfie as proof of the check-in
+	photo, ok := savePunchPhoto(c, schedule.Employee, geofence.department, "in")
+	if !ok {
+		return
+	}
+	photoReview := ""
+	if photo != nil {
+		photoReview = models.PhotoReviewPending
+	}
+
 	attendance := models.Attendance{
-		ScheduleID:    schedule.ID,
-		Date:          currentDate,
-		ClockIn:       request.ClockIn,
-		ClockInStatus: clockInStatus,
+		ScheduleID:        schedule.ID,
+		Date:              currentDate,
+		ClockInAt:         &now,
+		ClientClockIn:     request.ClockIn,
+		ClockInDrift:      drift,
+		ClockInStatus:     clockInStatus,
+		ClockInLatitude:   request.Latitude,
+		ClockInLongitude:  request.Longitude,
+		ClockInAccuracy:   request.Accuracy,
+		ClockInDistance:   geofence.Distance,
+		ClockInLocationID: geofence.LocationID,
+		ClockInGeofence:   geofence.Result,
+		ClockInKioskID:    geofence.KioskID,
+		ClockInPhoto:      photo,
+		PhotoReview:       photoReview,
+		IsHoliday:         holidayName != "",
+		HolidayName:       holidayName,
 	}
 
-	if err := models.DB.Create(&attendance).Error; err != nil {
+	// the kiosk code is only used up when the check-in is saved
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := recordKioskUse(tx, geofence.kioskUse); err != nil {
+			return err
+		}
+		return tx.Create(&attendance).Error
+	})
+	if err != nil {
+		if photo != nil {
+			utils.RemoveUpload(*photo)
+		}
+		if errors.Is(err, errKioskCodeUsed) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": kioskCodeUsedMessage,
+			})
+			return
+		}
+		// the unique index on schedule_id stops a concurrent double punch
+		var count int64
+		if models.DB.Model(&models.Attendance{}).Where("schedule_id = ?", schedule.ID).Count(&count); count > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": "You have already checked in to this schedule",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to create attendance record",
@@ -105,12 +232,23 @@ func CreateAttendance(c *gin.Context) {
 				"date_schedule": schedule.DateSchedule,
 				"status":        schedule.Status,
 			},
-			"date":             attendance.Date,
-			"clock_in":         attendance.ClockIn,
-			"clock_out":        attendance.ClockOut,
+			"date":            attendance.Date,
+			"clock_in":        attendance.ClockIn(),
+			"clock_out":       attendance.ClockOut(),
+			"clock_in_at":     attendance.ClockInAt,
+			"clock_out_at":    attendance.ClockOutAt,
+			"client_clock_in": attendance.ClientClockIn,
+			"clock_in_drift":  attendance.ClockInDrift,
+			"clock_in_location": formatPunchLocation(attendance.ClockInLatitude, attendance.ClockInLongitude,
+				attendance.ClockInAccuracy, attendance.ClockInDistance, attendance.ClockInLocationID, attendance.ClockInGeofence,
+				attendance.ClockInKioskID),
+			"clock_in_photo":   attendance.ClockInPhoto,
+			"photo_review":     attendance.PhotoReview,
 			"duration":         attendance.Duration,
 			"clock_in_status":  attendance.ClockInStatus,
 			"clock_out_status": attendance.ClockOutStatus,
+			"is_holiday":       attendance.IsHoliday,
+			"holiday_name":     attendance.HolidayName,
 			"created_at":       attendance.CreatedAt,
 			"updated_at":       attendance.UpdatedAt,
 		},
@@ -118,38 +256,17 @@ func CreateAttendance(c *gin.Context) {
 }
 
 // Helper function to validate clock in time and determine status
-func validateClockIn(clockIn string, scheduleStart string) (string, bool) {
-	// Parse clock in and schedule start times (format: HH:MM)
-	clockInParts := strings.Split(clockIn, ":")
-	scheduleParts := strings.Split(scheduleStart, ":")
-
-	if len(clockInParts) < 2 || len(scheduleParts) < 2 {
-		return "", false
-	}
-
-	// Convert to integers
-	clockInHour, _ := strconv.Atoi(clockInParts[0])
-	clockInMinute, _ := strconv.Atoi(clockInParts[1])
-	scheduleHour, _ := strconv.Atoi(scheduleParts[0])
-	scheduleMinute, _ := strconv.Atoi(scheduleParts[1])
-
-	// Convert times to minutes for easier comparison
-	clockInTotalMinutes := clockInHour*60 + clockInMinute
-	scheduleTotalMinutes := scheduleHour*60 + scheduleMinute
-
-	// Calculate one hour before start time
-	oneHourBeforeShift := scheduleTotalMinutes - 60
-
-	// If clock-in is before one hour before shift start, it's invalid
-	if clockInTotalMinutes < oneHourBeforeShift {
+func validateClockIn(clockIn time.Time, shiftStart time.Time) (string, bool) {
+	// Clock-in opens one hour before the shift starts
+	if clockIn.Before(shiftStart.Add(-clockInOpensBefore)) {
 		return "", false
 	}
 
 	// If clock-in is before or equal to shift start time, it's on time
-	if clockInTotalMinutes <= scheduleTotalMinutes {
+	if !clockIn.After(shiftStart) {
 		return "Tepat Waktu", true
 	}
 
 	// Otherwise, it's late
 	return "Terlambat", true
-}
\ No newline at end of file
+}
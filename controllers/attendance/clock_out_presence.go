@@ -1,18 +1,25 @@
 package attendance
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// errAlreadyCheckedOut means the attendance was closed by another check-out in the meantime
+var errAlreadyCheckedOut = errors.New("already checked out")
+
 type CheckOutRequest struct {
-	ClockOut string `json:"clock_out" binding:"required"`
+	// ClockOut is the time shown on the device (HH:MM), only kept for reference. The server time is recorded.
+	ClockOut string `json:"clock_out" form:"clock_out"`
+	// ScheduleID selects the schedule to check out from, by default the latest open check-in is used
+	ScheduleID uint `json:"schedule_id" form:"schedule_id"`
+	PunchLocation
 }
 
 // UpdateAttendance handles employee check-out
@@ -27,7 +34,7 @@ func UpdateAttendance(c *gin.Context) {
 	}
 
 	var request CheckOutRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
+	if err := bindPunchRequest(c, &request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
 			"message": "Invalid request format",
@@ -35,35 +42,38 @@ func UpdateAttendance(c *gin.Context) {
 		return
 	}
 
-	currentDate := time.Now().Format("2006-01-02")
-
-	// Find employee's schedule for today
-	var schedule models.Schedule
-	result := models.DB.Where("employee_id = ? AND date_schedule = ?", employeeId, currentDate).
-		Preload("Shift").Preload("Employee").Preload("Employee.Position").First(&schedule)
-
-	if result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+	// The server clock decides the check-out time, the device time is only compared with it
+	now := utils.Now()
+	drift, err := checkClientClock(request.ClockOut, now)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "Schedule not found for today",
+			"message": "clock_out must be a time like 15:30",
 		})
 		return
 	}
 
-	// Find today's attendance record
-	var attendance models.Attendance
-	attendanceResult := models.DB.Where("schedule_id = ? AND date = ?", schedule.ID, currentDate).First(&attendance)
-
-	if attendanceResult.Error != nil {
+	// Find the attendance record to close, an employee may work several shifts a day and a night shift
+	// is closed after midnight
+	attendance, err := findOpenAttendance(employeeId, now, request.ScheduleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   true,
 			"message": "No check-in record found for today. Please check-in first",
 		})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load attendance: " + err.Error(),
+		})
+		return
+	}
+	schedule := attendance.Schedule
 
 	// Check if already clocked out
-	if attendance.ClockOut != "" {
+	if attendance.ClockOutAt != nil {
 		c.JSON(http.StatusConflict, gin.H{
 			"error":   true,
 			"message": "You have already checked out today",
@@ -71,23 +81,105 @@ func UpdateAttendance(c *gin.Context) {
 		return
 	}
 
+	// Departments check where the employee clocks out with a kiosk code or the geofence
+	geofence, ok := checkPunchLocation(c, schedule.Employee, request.PunchLocation, now)
+	if !ok {
+		return
+	}
+
+	// Departments may ask for a selfie as proof of the check-out
+	photo, ok := savePunchPhoto(c, schedule.Employee, geofence.department, "out")
+	if !ok {
+		return
+	}
+	// a new selfie has to be reviewed again
+	photoReview := attendance.PhotoReview
+	if photo != nil {
+		photoReview = models.PhotoReviewPending
+	}
+
 	// Validate clock-out time
-	clockOutStatus := validateClockOut(request.ClockOut, schedule.Shift.EndTime)
+	_, shiftEnd, err := schedulePeriod(schedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to read the shift times: " + err.Error(),
+		})
+		return
+	}
+	clockOutStatus := validateClockOut(now, shiftEnd)
 
 	// Calculate duration between clock-in and clock-out
-	duration := calculateDuration(attendance.ClockIn, request.ClockOut)
+	duration := ""
+	if attendance.ClockInAt != nil {
+		duration = calculateDuration(*attendance.ClockInAt, now)
+	}
 
 	// Update attendance record
-	attendance.ClockOut = request.ClockOut
+	attendance.ClockOutAt = &now
+	attendance.ClientClockOut = request.ClockOut
+	attendance.ClockOutDrift = drift
 	attendance.ClockOutStatus = clockOutStatus
 	attendance.Duration = duration
-
-	// Use a partial update to avoid overwriting the date field with an incorrect format
-	if err := models.DB.Model(&attendance).Updates(map[string]interface{}{
-		"clock_out":        request.ClockOut,
-		"clock_out_status": clockOutStatus,
-		"duration":         duration,
-	}).Error; err != nil {
+	attendance.ClockOutLatitude = request.Latitude
+	attendance.ClockOutLongitude = request.Longitude
+	attendance.ClockOutAccuracy = request.Accuracy
+	attendance.ClockOutDistance = geofence.Distance
+	attendance.ClockOutLocationID = geofence.LocationID
+	attendance.ClockOutGeofence = geofence.Result
+	attendance.ClockOutKioskID = geofence.KioskID
+	attendance.ClockOutPhoto = photo
+	attendance.PhotoReview = photoReview
+
+	// Use a partial update to avoid overwriting the date field with an incorrect format, the kiosk code
+	// is only used up when the check-out is saved. Only an open attendance is closed, a concurrent
+	// check-out must not overwrite the first one.
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := recordKioskUse(tx, geofence.kioskUse); err != nil {
+			return err
+		}
+		result := tx.Model(&models.Attendance{}).Where("id = ? AND clock_out_at IS NULL", attendance.ID).Updates(map[string]interface{}{
+			"clock_out_at":          now,
+			"client_clock_out":      request.ClockOut,
+			"clock_out_drift":       drift,
+			"clock_out_status":      clockOutStatus,
+			"duration":              duration,
+			"clock_out_latitude":    request.Latitude,
+			"clock_out_longitude":   request.Longitude,
+			"clock_out_accuracy":    request.Accuracy,
+			"clock_out_distance":    geofence.Distance,
+			"clock_out_location_id": geofence.LocationID,
+			"clock_out_geofence":    geofence.Result,
+			"clock_out_kiosk_id":    geofence.KioskID,
+			"clock_out_photo":       photo,
+			"photo_review":          photoReview,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyCheckedOut
+		}
+		return nil
+	})
+	if err != nil {
+		if photo != nil {
+			utils.RemoveUpload(*photo)
+		}
+		if errors.Is(err, errKioskCodeUsed) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": kioskCodeUsedMessage,
+			})
+			return
+		}
+		if errors.Is(err, errAlreadyCheckedOut) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": "You have already checked out today",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to update attendance record: " + err.Error(),
@@ -115,11 +207,23 @@ func UpdateAttendance(c *gin.Context) {
 				"status":        schedule.Status,
 			},
 			"date":             attendance.Date,
-			"clock_in":         attendance.ClockIn,
-			"clock_out":        attendance.ClockOut,
+			"clock_in":         attendance.ClockIn(),
+			"clock_out":        attendance.ClockOut(),
+			"clock_in_at":      attendance.ClockInAt,
+			"clock_out_at":     attendance.ClockOutAt,
+			"client_clock_out": attendance.ClientClockOut,
+			"clock_out_drift":  attendance.ClockOutDrift,
+			"clock_out_location": formatPunchLocation(attendance.ClockOutLatitude, attendance.ClockOutLongitude,
+				attendance.ClockOutAccuracy, attendance.ClockOutDistance, attendance.ClockOutLocationID, attendance.ClockOutGeofence,
+				attendance.ClockOutKioskID),
+			"clock_in_photo":   attendance.ClockInPhoto,
+			"clock_out_photo":  attendance.ClockOutPhoto,
+			"photo_review":     attendance.PhotoReview,
 			"duration":         attendance.Duration,
 			"clock_in_status":  attendance.ClockInStatus,
 			"clock_out_status": attendance.ClockOutStatus,
+			"is_holiday":       attendance.IsHoliday,
+			"holiday_name":     attendance.HolidayName,
 			"created_at":       attendance.CreatedAt,
 			"updated_at":       attendance.UpdatedAt,
 		},
@@ -127,92 +231,12 @@ func UpdateAttendance(c *gin.Context) {
 }
 
 // Helper function to validate clock out time and determine status
-func validateClockOut(clockOut string, scheduleEnd string) string {
-	// Parse clock out and schedule end times (format: HH:MM)
-	clockOutParts := strings.Split(clockOut, ":")
-	scheduleEndParts := strings.Split(scheduleEnd, ":")
-
-	if len(clockOutParts) < 2 || len(scheduleEndParts) < 2 {
-		return "Invalid Format"
-	}
-
-	// Convert to integers
-	clockOutHour, _ := strconv.Atoi(clockOutParts[0])
-	clockOutMinute, _ := strconv.Atoi(clockOutParts[1])
-	scheduleEndHour, _ := strconv.Atoi(scheduleEndParts[0])
-	scheduleEndMinute, _ := strconv.Atoi(scheduleEndParts[1])
-
-	// Convert times to minutes for easier comparison
-	clockOutTotalMinutes := clockOutHour*60 + clockOutMinute
-	scheduleEndTotalMinutes := scheduleEndHour*60 + scheduleEndMinute
-
-	// Handle night shift scenario
-	isNightShift := isNightShiftSchedule(scheduleEnd)
-
-	if isNightShift {
-		// For night shift, if schedule end is earlier in the day (e.g., 06:00),
-		// it means it's the next day. We need to adjust our comparison.
-		if scheduleEndTotalMinutes < 720 { // Before 12:00 (noon)
-			// If clock out is after midnight but before end time, it's on time
-			if clockOutTotalMinutes < 720 && clockOutTotalMinutes >= scheduleEndTotalMinutes {
-				return "Tepat Waktu"
-			} else if clockOutTotalMinutes < scheduleEndTotalMinutes {
-				return "Pulang Lebih Awal"
-			}
-		}
-	} else {
-		// Normal day shift logic
-		if clockOutTotalMinutes < scheduleEndTotalMinutes {
-			return "Pulang Lebih Awal"
-		}
+func validateClockOut(clockOut time.Time, shiftEnd time.Time) string {
+	// Leaving before the shift ends, the end of a night shift is on the next day
+	if clockOut.Before(shiftEnd) {
+		return "Pulang Lebih Awal"
 	}
 
 	// If we reach here, it's on time or late (which is fine for checkout)
 	return "Tepat Waktu"
 }
-
-// Helper function to check if the schedule is a night shift
-func isNightShiftSchedule(endTime string) bool {
-	// If end time is in the early morning (e.g., 00:00-07:00),
-	// it's likely a night shift
-	endParts := strings.Split(endTime, ":")
-	endHour, _ := strconv.Atoi(endParts[0])
-
-	return endHour < 7
-}
-
-// Helper function to calculate duration between clock-in and clock-out
-func calculateDuration(clockIn, clockOut string) string {
-	// Parse clock in and clock out times
-	inParts := strings.Split(clockIn, ":")
-	outParts := strings.Split(clockOut, ":")
-
-	if len(inParts) < 2 || len(outParts) < 2 {
-		return "Invalid Format"
-	}
-
-	inHour, _ := strconv.Atoi(inParts[0])
-	inMinute, _ := strconv.Atoi(inParts[1])
-	outHour, _ := strconv.Atoi(outParts[0])
-	outMinute, _ := strconv.Atoi(outParts[1])
-
-	// Convert to total minutes
-	inTotalMinutes := inHour*60 + inMinute
-	outTotalMinutes := outHour*60 + outMinute
-
-	// Handle overnight shifts
-	if outTotalMinutes < inTotalMinutes {
-		// Add 24 hours (1440 minutes) to out time
-		outTotalMinutes += 1440
-	}
-
-	// Calculate duration in minutes
-	durationMinutes := outTotalMinutes - inTotalMinutes
-
-	// Convert back to hours and minutes
-	durationHours := durationMinutes / 60
-	remainingMinutes := durationMinutes % 60
-
-	// Format as "X jam Y menit"
-	return fmt.Sprintf("%d jam %d menit", durationHours, remainingMinutes)
-}
\ No newline at end of file
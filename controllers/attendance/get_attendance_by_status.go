@@ -78,11 +78,18 @@ func formatAttendanceResponse(attendances []models.Attendance) []gin.H {
 		formattedAttendance := gin.H{
 			"id":               attendance.ID,
 			"date":             attendance.Date,
-			"clock_in":         attendance.ClockIn,
-			"clock_out":        attendance.ClockOut,
+			"clock_in":         attendance.ClockIn(),
+			"clock_out":        attendance.ClockOut(),
+			"clock_in_at":      attendance.ClockInAt,
+			"clock_out_at":     attendance.ClockOutAt,
+			"clock_in_photo":   attendance.ClockInPhoto,
+			"clock_out_photo":  attendance.ClockOutPhoto,
+			"photo_review":     attendance.PhotoReview,
 			"duration":         attendance.Duration,
 			"clock_in_status":  attendance.ClockInStatus,
 			"clock_out_status": attendance.ClockOutStatus,
+			"is_holiday":       attendance.IsHoliday,
+			"holiday_name":     attendance.HolidayName,
 			"created_at":       attendance.CreatedAt,
 			"updated_at":       attendance.UpdatedAt,
 		}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,7 +20,7 @@ func GetAttendanceLastThreeDays(c *gin.Context) {
 		return
 	}
 	// Get current date
-	today := time.Now()
+	today := utils.Now()
 
 	// Calculate dates (today and 2 days before)
 	dates := []string{
@@ -78,10 +79,14 @@ func GetAttendanceLastThreeDays(c *gin.Context) {
 		respAtt := gin.H{
 			"id":               att.ID,
 			"date":             att.Date,
-			"clock_in":         att.ClockIn,
-			"clock_out":        att.ClockOut,
+			"clock_in":         att.ClockIn(),
+			"clock_out":        att.ClockOut(),
+			"clock_in_at":      att.ClockInAt,
+			"clock_out_at":     att.ClockOutAt,
 			"clock_in_status":  att.ClockInStatus,
 			"clock_out_status": att.ClockOutStatus,
+			"is_holiday":       att.IsHoliday,
+			"holiday_name":     att.HolidayName,
 			"created_at":       att.CreatedAt.Format(time.RFC3339),
 			"updated_at":       att.UpdatedAt.Format(time.RFC3339),
 		}
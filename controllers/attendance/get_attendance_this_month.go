@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,7 +22,7 @@ func GetAttendanceThisMonth(c *gin.Context) {
 	}
 
 	// Get current date info
-	now := time.Now()
+	now := utils.Now()
 	currentYear, currentMonth, _ := now.Date()
 
 	// Get first day of the month
@@ -36,14 +37,18 @@ func GetAttendanceThisMonth(c *gin.Context) {
 
 	// Response structure
 	type AttendanceResponse struct {
-		ID             uint   `json:"id"`
-		Date           string `json:"date"`
-		ClockIn        string `json:"clock_in"`
-		ClockOut       string `json:"clock_out"`
-		ClockInStatus  string `json:"clock_in_status"`
-		ClockOutStatus string `json:"clock_out_status"`
-		CreatedAt      string `json:"created_at"`
-		UpdatedAt      string `json:"updated_at"`
+		ID             uint       `json:"id"`
+		Date           string     `json:"date"`
+		ClockIn        string     `json:"clock_in"`
+		ClockOut       string     `json:"clock_out"`
+		ClockInAt      *time.Time `json:"clock_in_at"`
+		ClockOutAt     *time.Time `json:"clock_out_at"`
+		ClockInStatus  string     `json:"clock_in_status"`
+		ClockOutStatus string     `json:"clock_out_status"`
+		IsHoliday      bool       `json:"is_holiday"`
+		HolidayName    string     `json:"holiday_name"`
+		CreatedAt      string     `json:"created_at"`
+		UpdatedAt      string     `json:"updated_at"`
 	}
 
 	// First get the employee's schedules for this month
@@ -92,10 +97,14 @@ func GetAttendanceThisMonth(c *gin.Context) {
 		responseAttendances = append(responseAttendances, AttendanceResponse{
 			ID:             att.ID,
 			Date:           att.Date,
-			ClockIn:        att.ClockIn,
-			ClockOut:       att.ClockOut,
+			ClockIn:        att.ClockIn(),
+			ClockOut:       att.ClockOut(),
+			ClockInAt:      att.ClockInAt,
+			ClockOutAt:     att.ClockOutAt,
 			ClockInStatus:  att.ClockInStatus,
 			ClockOutStatus: att.ClockOutStatus,
+			IsHoliday:      att.IsHoliday,
+			HolidayName:    att.HolidayName,
 			CreatedAt:      att.CreatedAt.Format(time.RFC3339),
 			UpdatedAt:      att.UpdatedAt.Format(time.RFC3339),
 		})
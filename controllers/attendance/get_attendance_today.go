@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,7 +22,7 @@ func GetAttendanceToday(c *gin.Context) {
 	}
 
 	// Get current date
-	today := time.Now().Format("2006-01-02")
+	today := utils.Now().Format("2006-01-02")
 
 	// Define response structure
 	type AttendanceResponse struct {
@@ -44,35 +45,44 @@ func GetAttendanceToday(c *gin.Context) {
 		Date           string `json:"date"`
 		ClockIn        string `json:"clock_in"`
 		ClockOut       string `json:"clock_out"`
+		ClockInAt      *time.Time `json:"clock_in_at"`
+		ClockOutAt     *time.Time `json:"clock_out_at"`
+		ClientClockIn  string `json:"client_clock_in"`
+		ClientClockOut string `json:"client_clock_out"`
+		ClockInDrift   bool   `json:"clock_in_drift"`
+		ClockOutDrift  bool   `json:"clock_out_drift"`
+		ClockInLocation  gin.H `json:"clock_in_location"`
+		ClockOutLocation gin.H `json:"clock_out_location"`
+		ClockInPhoto   *string `json:"clock_in_photo"`
+		ClockOutPhoto  *string `json:"clock_out_photo"`
+		PhotoReview    string  `json:"photo_review"`
 		Duration       string `json:"duration"`
 		ClockInStatus  string `json:"clock_in_status"`
 		ClockOutStatus string `json:"clock_out_status"`
+		IsHoliday      bool   `json:"is_holiday"`
+		HolidayName    string `json:"holiday_name"`
 		CreatedAt      string `json:"created_at"`
 		UpdatedAt      string `json:"updated_at"`
 	}
 
-	// Get the employee's schedule for today
-	var schedule models.Schedule
-	if err := models.DB.
-		Preload("Employee").
-		Preload("Employee.Position").
-		Preload("Shift").
-		Where("employee_id = ? AND date_schedule = ?", employeeID, today).
-		First(&schedule).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+	// Get the attendance records of today's schedules, an employee may work several shifts a day
+	var attendances []models.Attendance
+	if err := models.DB.Joins("JOIN schedules ON schedules.id = attendances.schedule_id").
+		Preload("Schedule").
+		Preload("Schedule.Employee").
+		Preload("Schedule.Employee.Position").
+		Preload("Schedule.Shift").
+		Where("schedules.employee_id = ? AND attendances.date = ?", employeeID, today).
+		Order("attendances.id").
+		Find(&attendances).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
-			"message": "No schedule found for today",
+			"message": "Failed to load attendance: " + err.Error(),
 		})
 		return
 	}
 
-	// Get attendance record for today's schedule
-	var attendance models.Attendance
-	result := models.DB.
-		Where("schedule_id = ? AND date = ?", schedule.ID, today).
-		First(&attendance)
-
-	if result.Error != nil {
+	if len(attendances) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   true,
 			"message": "No attendance record found for today",
@@ -80,39 +90,61 @@ func GetAttendanceToday(c *gin.Context) {
 		return
 	}
 
-	// Prepare response
-	var response AttendanceResponse
-	response.ID = attendance.ID
-	response.Date = attendance.Date
-	response.ClockIn = attendance.ClockIn
-	response.ClockOut = attendance.ClockOut
-	response.Duration = attendance.Duration
-	response.ClockInStatus = attendance.ClockInStatus
-	response.ClockOutStatus = attendance.ClockOutStatus
-	response.CreatedAt = attendance.CreatedAt.Format(time.RFC3339)
-	response.UpdatedAt = attendance.UpdatedAt.Format(time.RFC3339)
+	responses := make([]AttendanceResponse, len(attendances))
+	for i, attendance := range attendances {
+		schedule := attendance.Schedule
+
+		// Prepare response
+		var response AttendanceResponse
+		response.ID = attendance.ID
+		response.Date = attendance.Date
+		response.ClockIn = attendance.ClockIn()
+		response.ClockOut = attendance.ClockOut()
+		response.ClockInAt = attendance.ClockInAt
+		response.ClockOutAt = attendance.ClockOutAt
+		response.ClientClockIn = attendance.ClientClockIn
+		response.ClientClockOut = attendance.ClientClockOut
+		response.ClockInDrift = attendance.ClockInDrift
+		response.ClockOutDrift = attendance.ClockOutDrift
+		response.ClockInLocation = formatPunchLocation(attendance.ClockInLatitude, attendance.ClockInLongitude,
+			attendance.ClockInAccuracy, attendance.ClockInDistance, attendance.ClockInLocationID, attendance.ClockInGeofence, attendance.ClockInKioskID)
+		response.ClockOutLocation = formatPunchLocation(attendance.ClockOutLatitude, attendance.ClockOutLongitude,
+			attendance.ClockOutAccuracy, attendance.ClockOutDistance, attendance.ClockOutLocationID, attendance.ClockOutGeofence, attendance.ClockOutKioskID)
+		response.ClockInPhoto = attendance.ClockInPhoto
+		response.ClockOutPhoto = attendance.ClockOutPhoto
+		response.PhotoReview = attendance.PhotoReview
+		response.Duration = attendance.Duration
+		response.ClockInStatus = attendance.ClockInStatus
+		response.ClockOutStatus = attendance.ClockOutStatus
+		response.IsHoliday = attendance.IsHoliday
+		response.HolidayName = attendance.HolidayName
+		response.CreatedAt = attendance.CreatedAt.Format(time.RFC3339)
+		response.UpdatedAt = attendance.UpdatedAt.Format(time.RFC3339)
+
+		// Set schedule data
+		response.Schedule.ID = schedule.ID
+		response.Schedule.DateSchedule = schedule.DateSchedule
+		response.Schedule.Status = schedule.Status
 
-	// Set schedule data
-	response.Schedule.ID = schedule.ID
-	response.Schedule.DateSchedule = schedule.DateSchedule
-	response.Schedule.Status = schedule.Status
-	
-	// Set shift data
-	response.Schedule.Shift.ID = schedule.Shift.ID
-	response.Schedule.Shift.Type = schedule.Shift.Type
-	response.Schedule.Shift.StartTime = schedule.Shift.StartTime
-	response.Schedule.Shift.EndTime = schedule.Shift.EndTime
+		// Set shift data
+		response.Schedule.Shift.ID = schedule.Shift.ID
+		response.Schedule.Shift.Type = schedule.Shift.Type
+		response.Schedule.Shift.StartTime = schedule.Shift.StartTime
+		response.Schedule.Shift.EndTime = schedule.Shift.EndTime
 
-	// Set employee data
-	response.Employee.Name = schedule.Employee.Name
-	if schedule.Employee.Position.PositionName != "" {
-		response.Employee.Position = schedule.Employee.Position.PositionName
+		// Set employee data
+		response.Employee.Name = schedule.Employee.Name
+		if schedule.Employee.Position.PositionName != "" {
+			response.Employee.Position = schedule.Employee.Position.PositionName
+		}
+		responses[i] = response
 	}
 
-	// Return response
+	// attendance_now is the latest check-in, attendances lists every check-in of today
 	c.JSON(http.StatusOK, gin.H{
 		"error":          false,
 		"message":        "Today's attendance data retrieved successfully",
-		"attendance_now": response,
+		"attendance_now": responses[len(responses)-1],
+		"attendances":    responses,
 	})
-}
\ No newline at end of file
+}
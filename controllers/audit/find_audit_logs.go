@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// FindAuditLogs returns the latest audit entries, optionally filtered by ?action= and ?employee_id= (admin)
+func FindAuditLogs(c *gin.Context) {
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "limit must be a number between 1 and 500",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	query := models.DB.Order("created_at DESC, id DESC").Limit(limit)
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if employeeID := c.Query("employee_id"); employeeID != "" {
+		query = query.Where("employee_id = ?", employeeID)
+	}
+
+	var logs []models.AuditLog
+	if err := query.Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve audit logs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Audit logs retrieved successfully",
+		"logs":    logs,
+	})
+}
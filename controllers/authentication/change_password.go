@@ -0,0 +1,134 @@
+package authentication
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ChangePassword changes the password of the authenticated employee and clears a forced password change
+func ChangePassword(c *gin.Context) {
+	employeeId, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	var input ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	var employee models.Employee
+	if err := models.DB.First(&employee, employeeId).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	if err := employee.CheckPassword(input.CurrentPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Current password is incorrect",
+		})
+		return
+	}
+
+	if input.CurrentPassword == input.NewPassword {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "New password must be different from the current password",
+		})
+		return
+	}
+
+	if err := employee.HashPassword(input.NewPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to hash password",
+		})
+		return
+	}
+
+	if err := models.DB.Model(&employee).Updates(map[string]interface{}{
+		"password":             employee.Password,
+		"must_change_password": false,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to change password",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Password changed successfully",
+	})
+}
+
+// ForcePasswordChange makes an employee change their password at the next login (admin)
+func ForcePasswordChange(c *gin.Context) {
+	var employee models.Employee
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&employee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	allowed, err := models.CanManageEmployee(models.DB, c.GetInt("employeeId"), employee.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check permissions: " + err.Error(),
+		})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot manage this employee, their role grants permissions you do not have",
+		})
+		return
+	}
+
+	if err := models.DB.Model(&employee).Update("must_change_password", true).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update employee",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": employee.Name + " must change their password at the next login",
+	})
+}
@@ -0,0 +1,88 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/notification"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ForgotPassword sends a single-use reset code through the configured notifier.
+// The response is the same whether the email exists or not.
+func ForgotPassword(c *gin.Context) {
+	var input ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	response := gin.H{
+		"error":   false,
+		"message": "If the email is registered, a password reset code has been sent",
+	}
+
+	var employee models.Employee
+	if err := models.DB.Where("email = ?", input.Email).First(&employee).Error; err != nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, expiresAt, err := utils.CreatePasswordResetToken(employee)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create password reset code",
+		})
+		return
+	}
+
+	if err := notification.Default.Send(passwordResetMessage(employee, token, expiresAt)); err != nil {
+		log.Printf("ERROR: failed to send password reset to %s: %v", employee.Email, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to send password reset code",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func passwordResetMessage(employee models.Employee, token string, expiresAt time.Time) notification.Message {
+	instruction := "Your password reset code: " + token
+	if resetURL := config.App.Password.ResetURL; resetURL != "" {
+		instruction = "Open this link to reset your password: " + resetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	return notification.Message{
+		To:      employee.Email,
+		Subject: "HotelQu password reset",
+		Body: fmt.Sprintf("Hello %s,\n\n%s\n\nThe code can be used once and expires at %s.\nIgnore this message if you did not ask for a reset.",
+			employee.Name, instruction, expiresAt.Format("02-01-2006 15:04")),
+	}
+}
@@ -2,7 +2,12 @@ package authentication
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
@@ -33,27 +38,55 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// refuse locked accounts and addresses before spending time on bcrypt
+	throttleEmail := utils.NormalizeLoginEmail(input.Email)
+	lock, err := utils.CheckLoginLock(throttleEmail, c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Database error",
+		})
+		return
+	}
+	if lock != nil {
+		respondLoginLocked(c, lock)
+		return
+	}
+
 	// search employee by email
 	var employee models.Employee
 	if err := models.DB.Where("email = ?", input.Email).First(&employee).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   true,
-			"message": "Invalid email or password",
-		})
+		// pay the bcrypt cost anyway so unknown emails are not answered faster
+		models.CheckDummyPassword(input.Password)
+		respondLoginFailed(c, throttleEmail, nil)
 		return
 	}
 
 	// verification password
 	if err := employee.CheckPassword(input.Password); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
+		respondLoginFailed(c, throttleEmail, &employee.Id)
+		return
+	}
+
+	if err := utils.RecordLoginSuccess(throttleEmail); err != nil {
+		log.Printf("ERROR: failed to reset login attempts of %s: %v", throttleEmail, err)
+	}
+
+	// only approved accounts may log in
+	if message, allowed := loginStatusMessage(employee); !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
 			"error":   true,
-			"message": "Invalid email or password",
+			"message": message,
+			"status":  employee.Status,
 		})
 		return
 	}
 
-	// Generate token JWT
-	token, err := utils.GenerateToken(employee)
+	// Generate access token JWT and refresh token
+	tokens, _, err := utils.IssueTokenPair(models.DB, employee, utils.SessionInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -66,9 +99,64 @@ func Login(c *gin.Context) {
 		"error":   false,
 		"message": "Login successful",
 		"loginResult": gin.H{
-			"id":    employee.Id,
-			"name":  employee.Name,
-			"token": token,
+			"id":                       employee.Id,
+			"name":                     employee.Name,
+			"token":                    tokens.AccessToken,
+			"token_expires_at":         tokens.AccessTokenExpiresAt.Format(time.RFC3339),
+			"refresh_token":            tokens.RefreshToken,
+			"refresh_token_expires_at": tokens.RefreshTokenExpiresAt.Format(time.RFC3339),
+			"must_change_password":     employee.MustChangePassword,
 		},
 	})
-}
\ No newline at end of file
+}
+
+// loginStatusMessage returns the error shown to employees whose account status does not allow login
+func loginStatusMessage(employee models.Employee) (string, bool) {
+	if employee.IsActiveOn(time.Now().Format("2006-01-02")) {
+		return "", true
+	}
+
+	switch employee.Status {
+	case models.EmployeeStatusPending:
+		return "Your account is waiting for admin approval", false
+	case models.EmployeeStatusRejected:
+		return "Your registration has been rejected", false
+	case models.EmployeeStatusDisabled:
+		return "Your account has been disabled", false
+	case models.EmployeeStatusTerminated:
+		return "Your employment has ended", false
+	}
+	return "Your account is not active", false
+}
+
+// respondLoginFailed counts the failed attempt and answers 401, or 429 when it started a lockout
+func respondLoginFailed(c *gin.Context, email string, employeeID *int) {
+	lock, err := utils.RecordLoginFailure(email, c.ClientIP(), employeeID)
+	if err != nil {
+		log.Printf("ERROR: failed to record login failure of %s: %v", email, err)
+	}
+	if lock != nil {
+		respondLoginLocked(c, lock)
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"error":   true,
+		"message": "Invalid email or password",
+	})
+}
+
+// respondLoginLocked answers 429 with the time left until the lockout ends
+func respondLoginLocked(c *gin.Context, lock *models.LoginThrottle) {
+	retryAfter := int(math.Ceil(time.Until(*lock.LockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       true,
+		"message":     fmt.Sprintf("Too many failed login attempts, try again in %s", time.Duration(retryAfter)*time.Second),
+		"retry_after": retryAfter,
+	})
+}
@@ -0,0 +1,116 @@
+package authentication
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FindLoginLocks lists the accounts and ip addresses that are locked out right now (admin)
+func FindLoginLocks(c *gin.Context) {
+	var locks []models.LoginThrottle
+	if err := models.DB.Where("locked_until > ?", time.Now()).Order("locked_until DESC").Find(&locks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve login locks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Login locks retrieved successfully",
+		"locks":   locks,
+	})
+}
+
+// UnlockLogin removes a lockout by id, used for ip addresses (admin)
+func UnlockLogin(c *gin.Context) {
+	var throttle models.LoginThrottle
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&throttle).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Login lock not found",
+		})
+		return
+	}
+
+	var employeeID *int
+	if throttle.Kind == models.LoginThrottleAccount {
+		var employee models.Employee
+		if err := models.DB.Select("id").Where("LOWER(email) = ?", throttle.Identifier).First(&employee).Error; err == nil {
+			employeeID = &employee.Id
+		}
+	}
+
+	if err := utils.UnlockLogin(throttle, c.GetInt("employeeId"), employeeID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to unlock login",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": throttle.Identifier + " has been unlocked",
+	})
+}
+
+// UnlockEmployeeLogin clears the failed attempts and lockout of an employee account (admin)
+func UnlockEmployeeLogin(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid employee ID",
+		})
+		return
+	}
+
+	var employee models.Employee
+	if err := models.DB.First(&employee, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	var throttle models.LoginThrottle
+	err = models.DB.Where("kind = ? AND identifier = ?", models.LoginThrottleAccount, utils.NormalizeLoginEmail(employee.Email)).
+		First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, gin.H{
+			"error":   false,
+			"message": employee.Name + " has no failed login attempts",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Database error",
+		})
+		return
+	}
+
+	if err := utils.UnlockLogin(throttle, c.GetInt("employeeId"), &employee.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to unlock login",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": employee.Name + " has been unlocked",
+	})
+}
@@ -0,0 +1,125 @@
+package authentication
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Logout revokes the access token used for the request and, when given, its refresh token
+func Logout(c *gin.Context) {
+	value, exists := c.Get("tokenClaims")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+	claims := value.(*utils.JWTClaims)
+
+	// body is optional, a client that lost its refresh token can still log out
+	var input LogoutInput
+	_ = c.ShouldBindJSON(&input)
+
+	if input.RefreshToken != "" {
+		if err := utils.RevokeRefreshToken(claims.Id, input.RefreshToken); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to revoke refresh token",
+			})
+			return
+		}
+	}
+
+	if err := utils.RevokeAccessToken(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to revoke token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Logout successful",
+	})
+}
+
+// LogoutAll logs the authenticated employee out of every device
+func LogoutAll(c *gin.Context) {
+	employeeId, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	if err := utils.RevokeAllSessions(employeeId.(int)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to logout from all devices",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Logged out from all devices",
+	})
+}
+
+// RevokeEmployeeSessions lets an admin log an employee out of every device (e.g. lost phone)
+func RevokeEmployeeSessions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid employee ID",
+		})
+		return
+	}
+
+	var employee models.Employee
+	if err := models.DB.First(&employee, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	allowed, err := models.CanManageEmployee(models.DB, c.GetInt("employeeId"), employee.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check permissions: " + err.Error(),
+		})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot manage this employee, their role grants permissions you do not have",
+		})
+		return
+	}
+
+	if err := utils.RevokeAllSessions(employee.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to revoke employee sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "All sessions of " + employee.Name + " have been revoked",
+	})
+}
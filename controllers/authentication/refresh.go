@@ -0,0 +1,77 @@
+package authentication
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Refresh exchanges a refresh token for a new access token and a new refresh token
+func Refresh(c *gin.Context) {
+	var input RefreshInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	tokens, err := utils.RotateRefreshToken(input.RefreshToken, utils.SessionInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrRefreshTokenInvalid), errors.Is(err, utils.ErrRefreshTokenExpired):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Invalid or expired refresh token",
+			})
+		case errors.Is(err, utils.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Refresh token already used, all sessions have been logged out",
+			})
+		case errors.Is(err, utils.ErrEmployeeInactive):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "Your account is not active",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to refresh token",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Token refreshed successfully",
+		"refreshResult": gin.H{
+			"token":                    tokens.AccessToken,
+			"token_expires_at":         tokens.AccessTokenExpiresAt.Format(time.RFC3339),
+			"refresh_token":            tokens.RefreshToken,
+			"refresh_token_expires_at": tokens.RefreshTokenExpiresAt.Format(time.RFC3339),
+		},
+	})
+}
@@ -3,7 +3,9 @@ package authentication
 import (
 	"errors"
 	"net/http"
+	"strings"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/gin-gonic/gin"
@@ -65,6 +67,12 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	// new accounts wait for admin approval, except the configured admin account
+	status := models.EmployeeStatusPending
+	if config.App.Admin.Email != "" && strings.EqualFold(input.Email, config.App.Admin.Email) {
+		status = models.EmployeeStatusActive
+	}
+
 	// create new employee
 	employee := models.Employee{
 		Name:       input.Name,
@@ -72,6 +80,7 @@ func Register(c *gin.Context) {
 		Password:   input.Password,
 		Phone:      input.Phone,
 		PositionId: position.Id,
+		Status:     status,
 	}
 
 	if err := models.DB.Create(&employee).Error; err != nil {
@@ -82,8 +91,14 @@ func Register(c *gin.Context) {
 		return
 	}
 
+	message := "Registration successful, please wait for admin approval"
+	if status == models.EmployeeStatusActive {
+		message = "Registration successful"
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"error":   false,
-		"message": "Registration successful",
+		"message": message,
+		"status":  status,
 	})
 }
\ No newline at end of file
@@ -0,0 +1,114 @@
+package calendar
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetCalendarFeed tells whether the caller has a feed token and when a calendar app last used it.
+// The token itself is only shown when it is created.
+func GetCalendarFeed(c *gin.Context) {
+	employeeID := c.GetInt("employeeId")
+
+	var feedToken models.CalendarFeedToken
+	err := models.DB.Where("employee_id = ?", employeeID).First(&feedToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, gin.H{
+			"error":   false,
+			"message": "No calendar feed created yet",
+			"feed":    nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve calendar feed: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Calendar feed retrieved successfully",
+		"feed":    formatFeedToken(feedToken),
+	})
+}
+
+// CreateCalendarFeed creates the caller's feed token, or replaces it so the old feed URLs stop working
+func CreateCalendarFeed(c *gin.Context) {
+	employeeID := c.GetInt("employeeId")
+
+	plainToken, feedToken, err := utils.CreateCalendarFeedToken(employeeID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create calendar feed: " + err.Error(),
+		})
+		return
+	}
+
+	feed := formatFeedToken(feedToken)
+	feed["token"] = plainToken
+	feed["schedule_url"] = feedURL(c, plainToken, "schedule.ics")
+	feed["department_url"] = feedURL(c, plainToken, "department.ics")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": "Calendar feed created, add the URL to your calendar app. It is only shown once.",
+		"feed":    feed,
+	})
+}
+
+// RevokeCalendarFeed deletes the caller's feed token, calendar apps subscribed to it stop receiving updates
+func RevokeCalendarFeed(c *gin.Context) {
+	employeeID := c.GetInt("employeeId")
+
+	result := models.DB.Where("employee_id = ?", employeeID).Delete(&models.CalendarFeedToken{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to revoke calendar feed: " + result.Error.Error(),
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "No calendar feed to revoke",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Calendar feed revoked successfully",
+	})
+}
+
+func formatFeedToken(feedToken models.CalendarFeedToken) gin.H {
+	var lastUsedAt *string
+	if feedToken.LastUsedAt != nil {
+		value := feedToken.LastUsedAt.Format(time.RFC3339)
+		lastUsedAt = &value
+	}
+	return gin.H{
+		"created_at":   feedToken.CreatedAt.Format(time.RFC3339),
+		"last_used_at": lastUsedAt,
+	}
+}
+
+// feedURL builds the absolute URL of a feed from the request, behind a proxy X-Forwarded-Proto gives the scheme
+func feedURL(c *gin.Context, token, name string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + "/api/calendar/" + token + "/" + name
+}
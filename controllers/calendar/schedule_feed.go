@@ -0,0 +1,157 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// the feeds cover the schedules from a month ago to three months ahead
+const (
+	feedDaysBack  = 30
+	feedDaysAhead = 90
+)
+
+// EmployeeScheduleFeed serves the published schedules of the token's owner as an iCalendar file
+func EmployeeScheduleFeed(c *gin.Context) {
+	feedToken, ok := loadFeedToken(c)
+	if !ok {
+		return
+	}
+	employee := feedToken.Employee
+
+	schedules, err := feedSchedules(models.DB.Where("schedules.employee_id = ?", employee.Id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load schedules: " + err.Error(),
+		})
+		return
+	}
+
+	events := make([]utils.ICalFeedEvent, 0, len(schedules))
+	for _, schedule := range schedules {
+		if event, ok := scheduleEvent(schedule, schedule.Shift.Type+" shift"); ok {
+			events = append(events, event)
+		}
+	}
+	writeFeed(c, "HotelQu - "+employee.Name, events)
+}
+
+// DepartmentScheduleFeed serves the published schedules of the owner's department, the owner needs
+// schedule:read when the feed is read
+func DepartmentScheduleFeed(c *gin.Context) {
+	feedToken, ok := loadFeedToken(c)
+	if !ok {
+		return
+	}
+	employee := feedToken.Employee
+	if !employee.Position.Role.HasPermission(models.PermissionScheduleRead) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You don't have permission to access this resource",
+		})
+		return
+	}
+
+	departmentEmployees := models.DB.Model(&models.Employee{}).
+		Select("employees.id").
+		Joins("JOIN positions ON positions.id = employees.position_id").
+		Where("positions.department_id = ?", employee.Position.DepartmentId)
+	schedules, err := feedSchedules(models.DB.Where("schedules.employee_id IN (?)", departmentEmployees))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load schedules: " + err.Error(),
+		})
+		return
+	}
+
+	events := make([]utils.ICalFeedEvent, 0, len(schedules))
+	for _, schedule := range schedules {
+		if event, ok := scheduleEvent(schedule, schedule.Employee.Name+" - "+schedule.Shift.Type); ok {
+			events = append(events, event)
+		}
+	}
+	writeFeed(c, "HotelQu - "+employee.Position.Department.DepartmentName, events)
+}
+
+// loadFeedToken resolves the :token of the URL, answering 404 for unknown or revoked tokens and
+// 403 when the owner is no longer active
+func loadFeedToken(c *gin.Context) (models.CalendarFeedToken, bool) {
+	feedToken, err := utils.FindCalendarFeedToken(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, utils.ErrCalendarFeedTokenInvalid) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Calendar feed not found",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to load calendar feed: " + err.Error(),
+			})
+		}
+		return feedToken, false
+	}
+	if !feedToken.Employee.IsActiveOn(utils.Now().Format("2006-01-02")) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "Employee is not active",
+		})
+		return feedToken, false
+	}
+	return feedToken, true
+}
+
+// feedSchedules returns the published schedules of the query in the feed window that are not covered by leave
+func feedSchedules(query *gorm.DB) ([]models.Schedule, error) {
+	now := utils.Now()
+	var schedules []models.Schedule
+	err := query.Preload("Shift").Preload("Employee").
+		Where("schedules.draft = ? AND schedules.leave_request_id IS NULL AND schedules.date_schedule BETWEEN ? AND ?", false,
+			now.AddDate(0, 0, -feedDaysBack).Format("2006-01-02"), now.AddDate(0, 0, feedDaysAhead).Format("2006-01-02")).
+		Order("schedules.date_schedule, schedules.id").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// scheduleEvent turns a schedule into a calendar event, a shift ending before it starts ends the next day
+func scheduleEvent(schedule models.Schedule, summary string) (utils.ICalFeedEvent, bool) {
+	date := schedule.DateSchedule
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return utils.ICalFeedEvent{}, false
+	}
+	start, end, err := schedule.Shift.Period(day)
+	if err != nil {
+		return utils.ICalFeedEvent{}, false
+	}
+	return utils.ICalFeedEvent{
+		UID:         fmt.Sprintf("schedule-%d@hotelqu", schedule.ID),
+		Summary:     summary,
+		Description: fmt.Sprintf("%s shift %s - %s, status: %s", schedule.Shift.Type, schedule.Shift.StartTime, schedule.Shift.EndTime, schedule.Status),
+		Start:       start,
+		End:         end,
+		Modified:    schedule.UpdatedAt,
+	}, true
+}
+
+func writeFeed(c *gin.Context, name string, events []utils.ICalFeedEvent) {
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="schedule.ics"`)
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	if err := utils.WriteICalendar(c.Writer, name, events); err != nil {
+		c.Error(err)
+	}
+}
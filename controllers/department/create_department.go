@@ -27,9 +27,24 @@ func StoreDepartment(c *gin.Context) {
 		return
 	}
 
+	if !input.workLocationExists() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Work location not found",
+		})
+		return
+	}
+
 	department := models.Department{
 		ParentDepartmentId: input.ParentDepartmentId,
 		DepartmentName:     input.DepartmentName,
+		WorkLocationID:     input.WorkLocationID,
+		GeofencePolicy:     input.GeofencePolicy,
+		KioskRequired:      input.KioskRequired != nil && *input.KioskRequired,
+		SelfieRequired:     input.SelfieRequired != nil && *input.SelfieRequired,
+	}
+	if department.GeofencePolicy == "" {
+		department.GeofencePolicy = models.GeofencePolicyOff
 	}
 	models.DB.Create(&department)
 
@@ -1,6 +1,25 @@
 package department
 
+import "github.com/OrryFrasetyo/go-api-hotelqu/models"
+
 type ValidateDepartmentInput struct {
 	ParentDepartmentId *int   `json:"parent_department_id"`
 	DepartmentName     string `json:"department_name" binding:"required"`
+	// WorkLocationID limits the geofence of the department to one work location
+	WorkLocationID *uint  `json:"work_location_id"`
+	GeofencePolicy string `json:"geofence_policy" binding:"omitempty,oneof=off flag reject"`
+	// KioskRequired only accepts check-ins and check-outs with a kiosk code
+	KioskRequired *bool `json:"kiosk_required"`
+	// SelfieRequired only accepts check-ins and check-outs with a selfie photo
+	SelfieRequired *bool `json:"selfie_required"`
+}
+
+// workLocationExists reports whether the work location of the input can be used, nil is always fine
+func (input ValidateDepartmentInput) workLocationExists() bool {
+	if input.WorkLocationID == nil {
+		return true
+	}
+	var count int64
+	models.DB.Model(&models.WorkLocation{}).Where("id = ?", *input.WorkLocationID).Count(&count)
+	return count > 0
 }
\ No newline at end of file
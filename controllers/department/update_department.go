@@ -36,6 +36,14 @@ func UpdateDepartment(c *gin.Context) {
 		return
 	}
 
+	if !input.workLocationExists() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Work location not found",
+		})
+		return
+	}
+
 	models.DB.Model(&department).Updates(input)
 
 	c.JSON(200, gin.H{
@@ -0,0 +1,27 @@
+package employee
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// FindEmployeeById returns any employee for HR (admin)
+func FindEmployeeById(c *gin.Context) {
+	var employee models.Employee
+	if err := models.DB.Preload("Position.Department").Preload("Position.Role").
+		Where("id = ?", c.Param("id")).First(&employee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Employee retrieved successfully",
+		"employee": formatEmployee(employee),
+	})
+}
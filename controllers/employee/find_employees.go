@@ -0,0 +1,116 @@
+package employee
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// columns the employee directory can be sorted by
+var employeeSortColumns = map[string]string{
+	"id":         "employees.id",
+	"name":       "employees.name",
+	"email":      "employees.email",
+	"status":     "employees.status",
+	"position":   "positions.position_name",
+	"department": "departments.department_name",
+}
+
+// FindEmployees is the HR employee directory (admin).
+// Query: q (name, email or phone), department_id, position_id, status (comma separated),
+// sort (e.g. name or -name), page and per_page.
+func FindEmployees(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "page must be a positive number",
+		})
+		return
+	}
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	if err != nil || perPage < 1 || perPage > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "per_page must be a number between 1 and 100",
+		})
+		return
+	}
+
+	sort := c.DefaultQuery("sort", "name")
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		sort, direction = sort[1:], "DESC"
+	}
+	sortColumn, ok := employeeSortColumns[sort]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "sort must be one of id, name, email, status, position or department (prefix with - for descending)",
+		})
+		return
+	}
+
+	query := models.DB.Model(&models.Employee{}).
+		Joins("JOIN positions ON positions.id = employees.position_id").
+		Joins("LEFT JOIN departments ON departments.id = positions.department_id")
+
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := "%" + strings.ToLower(q) + "%"
+		query = query.Where("LOWER(employees.name) LIKE ? OR LOWER(employees.email) LIKE ? OR employees.phone LIKE ?", like, like, like)
+	}
+	if departmentId := c.Query("department_id"); departmentId != "" {
+		query = query.Where("positions.department_id = ?", departmentId)
+	}
+	if positionId := c.Query("position_id"); positionId != "" {
+		query = query.Where("employees.position_id = ?", positionId)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("employees.status IN ?", strings.Split(status, ","))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to count employees: " + err.Error(),
+		})
+		return
+	}
+
+	var employees []models.Employee
+	if err := query.Select("employees.*").
+		Preload("Position.Department").
+		Preload("Position.Role").
+		Order(sortColumn + " " + direction).
+		Order("employees.id " + direction).
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&employees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve employees: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(employees))
+	for _, employee := range employees {
+		data = append(data, formatEmployee(employee))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":     false,
+		"message":   "Employees retrieved successfully",
+		"employees": data,
+		"pagination": gin.H{
+			"page":        page,
+			"per_page":    perPage,
+			"total":       total,
+			"total_pages": (total + int64(perPage) - 1) / int64(perPage),
+		},
+	})
+}
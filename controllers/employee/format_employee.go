@@ -0,0 +1,44 @@
+package employee
+
+import (
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// formatEmployee is the admin view of an employee, the Position.Department and Position.Role relations must be loaded
+func formatEmployee(employee models.Employee) gin.H {
+	var terminationDate *string
+	if employee.TerminationDate != nil {
+		date := *employee.TerminationDate
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		if t, err := time.Parse("2006-01-02", date); err == nil {
+			formatted := t.Format("02-01-2006")
+			terminationDate = &formatted
+		}
+	}
+
+	return gin.H{
+		"id":    employee.Id,
+		"name":  employee.Name,
+		"email": employee.Email,
+		"phone": employee.Phone,
+		"photo": employee.Photo,
+		"position": gin.H{
+			"id":   employee.Position.Id,
+			"name": employee.Position.PositionName,
+		},
+		"department": gin.H{
+			"id":   employee.Position.Department.Id,
+			"name": employee.Position.Department.DepartmentName,
+		},
+		"role":                 employee.Position.Role.Name,
+		"status":               employee.Status,
+		"termination_date":     terminationDate,
+		"status_reason":        employee.StatusReason,
+		"must_change_password": employee.MustChangePassword,
+	}
+}
@@ -2,6 +2,7 @@ package employee
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/gin-gonic/gin"
@@ -32,11 +33,12 @@ func GetEmployeesForDepartment(c *gin.Context) {
 	// Mendapatkan departemen ID dari karyawan yang login
 	departmentID := employee.Position.DepartmentId
 
-	// Mendapatkan semua karyawan dari departemen yang sama
+	// Mendapatkan semua karyawan aktif dari departemen yang sama
 	var employees []models.Employee
 	if err := models.DB.
 		Joins("JOIN positions ON employees.position_id = positions.id").
 		Where("positions.department_id = ?", departmentID).
+		Scopes(models.ActiveOn(time.Now().Format("2006-01-02"))).
 		Find(&employees).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -18,7 +18,7 @@ func GetProfile(c *gin.Context) {
 	}
 
 	var employee models.Employee
-	if err := models.DB.Preload("Position.Department").First(&employee, employeeId).Error; err != nil {
+	if err := models.DB.Preload("Position.Department").Preload("Position.Role.Permissions").First(&employee, employeeId).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   true,
 			"message": "Employee not found",
@@ -40,13 +40,15 @@ func GetProfile(c *gin.Context) {
 		"error":   false,
 		"message": "Profile retrieved successfully",
 		"profile": gin.H{
-			"id":         employee.Id,
-			"name":       employee.Name,
-			"email":      employee.Email,
-			"phone":      employee.Phone,
-			"position":   employee.Position.PositionName,
-			"department": departmentName,
-			"photo":      photoURL,
+			"id":          employee.Id,
+			"name":        employee.Name,
+			"email":       employee.Email,
+			"phone":       employee.Phone,
+			"position":    employee.Position.PositionName,
+			"department":  departmentName,
+			"role":        employee.Position.Role.Name,
+			"permissions": employee.Position.Role.PermissionNames(),
+			"photo":       photoURL,
 		},
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,9 @@
+package employee
+
+// UpdateEmployeeInput is used by admins to edit another employee, empty fields are left unchanged
+type UpdateEmployeeInput struct {
+	Name       string `json:"name" binding:"omitempty,max=100"`
+	Email      string `json:"email" binding:"omitempty,email,max=50"`
+	Phone      string `json:"phone" binding:"omitempty,max=16"`
+	PositionId *int   `json:"position_id"`
+}
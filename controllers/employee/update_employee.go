@@ -0,0 +1,140 @@
+package employee
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// UpdateEmployee lets HR edit the profile and position of another employee (admin).
+// Account status is changed through the lifecycle endpoints.
+func UpdateEmployee(c *gin.Context) {
+	var employee models.Employee
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&employee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	var input UpdateEmployeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	updates := map[string]interface{}{}
+	var changes []string
+
+	if input.Name != "" && input.Name != employee.Name {
+		updates["name"] = input.Name
+		changes = append(changes, fmt.Sprintf("name %q -> %q", employee.Name, input.Name))
+	}
+	if input.Phone != "" && input.Phone != employee.Phone {
+		updates["phone"] = input.Phone
+		changes = append(changes, fmt.Sprintf("phone %q -> %q", employee.Phone, input.Phone))
+	}
+	if input.Email != "" && !strings.EqualFold(input.Email, employee.Email) {
+		var count int64
+		models.DB.Model(&models.Employee{}).Where("email = ? AND id != ?", input.Email, employee.Id).Count(&count)
+		if count > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": "Email already registered",
+			})
+			return
+		}
+		updates["email"] = input.Email
+		changes = append(changes, fmt.Sprintf("email %q -> %q", employee.Email, input.Email))
+	}
+	if input.PositionId != nil && *input.PositionId != employee.PositionId {
+		if employee.Id == c.GetInt("employeeId") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "You cannot change your own position",
+			})
+			return
+		}
+		var position models.Position
+		if err := models.DB.Preload("Role.Permissions").Where("id = ?", *input.PositionId).First(&position).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Position not found!",
+			})
+			return
+		}
+		// the role of the old and the new position must not grant more than the editor holds
+		var actor, current models.Employee
+		if err := models.DB.Preload("Position.Role.Permissions").First(&actor, c.GetInt("employeeId")).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Employee not found",
+			})
+			return
+		}
+		models.DB.Preload("Position.Role.Permissions").First(&current, employee.Id)
+		if !actor.Position.Role.CanGrant(position.Role) || !actor.Position.Role.CanGrant(current.Position.Role) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "You cannot move this employee, the position role grants permissions you do not have",
+			})
+			return
+		}
+		updates["position_id"] = position.Id
+		changes = append(changes, fmt.Sprintf("position %d -> %d", employee.PositionId, position.Id))
+	}
+
+	if len(updates) > 0 {
+		actorID := c.GetInt("employeeId")
+		err := models.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&employee).Updates(updates).Error; err != nil {
+				return err
+			}
+			return models.RecordAudit(tx, models.AuditLog{
+				Action:     models.AuditEmployeeUpdate,
+				ActorID:    &actorID,
+				EmployeeID: &employee.Id,
+				IPAddress:  c.ClientIP(),
+				Detail:     strings.Join(changes, ", "),
+			})
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to update employee: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	models.DB.Preload("Position.Department").Preload("Position.Role").First(&employee, employee.Id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Employee updated successfully",
+		"employee": formatEmployee(employee),
+	})
+}
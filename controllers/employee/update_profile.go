@@ -3,15 +3,12 @@ package employee
 import (
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
@@ -19,8 +16,10 @@ import (
 
 type UpdateProfileInput struct {
 	Name     string `form:"name" binding:"required"`
-	Password string `form:"password"`
-	Phone    string `form:"phone" binding:"required"`
+	Password string `form:"password" binding:"omitempty,min=8"`
+	// CurrentPassword is required when Password is set
+	CurrentPassword string `form:"current_password"`
+	Phone           string `form:"phone" binding:"required"`
 }
 
 // UpdateProfile handles the profile update of the authenticated employee
@@ -73,94 +72,47 @@ func UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	// Handle file upload if a photo is provided
-	file, err := c.FormFile("photo")
-	if err != nil && err != http.ErrMissingFile {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   true,
-			"message": "Invalid file upload",
-		})
-		return
-	}
-
-	var photoPath *string
-	if file != nil {
-		// Validate file size (max 2MB)
-		if file.Size > 2*1024*1024 {
+	// Changing the password requires the current one
+	if input.Password != "" {
+		if input.CurrentPassword == "" {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   true,
-				"message": "File too large (max 2MB)",
+				"message": "Current password is required to change the password",
 			})
 			return
 		}
-
-		// Validate file type
-		fileExt := strings.ToLower(filepath.Ext(file.Filename))
-		if fileExt != ".jpg" && fileExt != ".jpeg" && fileExt != ".png" {
+		if err := employee.CheckPassword(input.CurrentPassword); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   true,
-				"message": "Only JPG, JPEG, and PNG files are allowed",
+				"message": "Current password is incorrect",
 			})
 			return
 		}
+	}
 
-		// Create uploads directory if it doesn't exist
-		uploadDir := "./uploads"
-		if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-			err := os.MkdirAll(uploadDir, 0755)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error":   true,
-					"message": "Failed to create upload directory",
-				})
-				return
-			}
-		}
-
-		// Generate unique filename
-		filename := fmt.Sprintf("%d_%d%s", employeeId, time.Now().Unix(), fileExt)
-		filepath := filepath.Join(uploadDir, filename)
-
-		// Save the file
-		src, err := file.Open()
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   true,
-				"message": "Failed to open uploaded file",
-			})
-			return
-		}
-		defer src.Close()
+	// Handle file upload if a photo is provided
+	file, err := c.FormFile("photo")
+	if err != nil && err != http.ErrMissingFile {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid file upload",
+		})
+		return
+	}
 
-		dst, err := os.Create(filepath)
+	var photoPath *string
+	if file != nil {
+		// Validate size (upload.max_size_mb, default 2MB) and type, then store the photo
+		relativePath, err := utils.SaveImage(file, "", fmt.Sprintf("%d_%d", employeeId, time.Now().Unix()))
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			status, message := errormessage.GetUploadErrorMsg(err)
+			c.JSON(status, gin.H{
 				"error":   true,
-				"message": "Failed to create destination file",
+				"message": message,
 			})
 			return
 		}
-		defer dst.Close()
-
-		if _, err = io.Copy(dst, src); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   true,
-				"message": "Failed to save file",
-			})
-			return
-		}
-
-		// Set photo path for database (relative URL)
-		relativePath := "/uploads/" + filename
 		photoPath = &relativePath
-
-		// Delete old photo if exists
-		if employee.Photo != nil && *employee.Photo != "" {
-			oldPhotoPath := "." + *employee.Photo
-			if _, err := os.Stat(oldPhotoPath); err == nil {
-				os.Remove(oldPhotoPath)
-			}
-		}
 	}
 
 	// Update employee data
@@ -176,15 +128,20 @@ func UpdateProfile(c *gin.Context) {
 			})
 			return
 		}
+		employee.MustChangePassword = false
 	}
 
 	// Update photo if a new one was uploaded
+	oldPhoto := employee.Photo
 	if photoPath != nil {
 		employee.Photo = photoPath
 	}
 
 	// Save changes to database
 	if err := models.DB.Save(&employee).Error; err != nil {
+		if photoPath != nil {
+			utils.RemoveUpload(*photoPath)
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to update profile",
@@ -192,6 +149,11 @@ func UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	// Delete the old photo once the new one is saved
+	if photoPath != nil && oldPhoto != nil && *oldPhoto != "" {
+		utils.RemoveUpload(*oldPhoto)
+	}
+
 	if err := models.DB.Preload("Position.Department").First(&employee, employeeId).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
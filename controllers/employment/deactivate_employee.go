@@ -0,0 +1,76 @@
+package employment
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// DeactivateEmployee disables an active employee right away and logs them out of every device (admin)
+func DeactivateEmployee(c *gin.Context) {
+	employee, ok := findTargetEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input DeactivateEmployeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	if !employee.IsActiveOn(time.Now().Format("2006-01-02")) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only active employees can be deactivated",
+		})
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	removed, err := applyStatusChange(c, &employee, statusChange{
+		updates: map[string]interface{}{
+			"status":           models.EmployeeStatusDisabled,
+			"termination_date": nil,
+			"status_reason":    input.Reason,
+		},
+		action:               models.AuditEmployeeDeactivate,
+		detail:               "Deactivated: " + input.Reason,
+		revokeSessions:       true,
+		removeSchedulesAfter: today,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to deactivate employee: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":             false,
+		"message":           employee.Name + " has been deactivated",
+		"employee":          formatEmployment(employee),
+		"removed_schedules": removed,
+	})
+}
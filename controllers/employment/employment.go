@@ -0,0 +1,165 @@
+package employment
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// statusChange is applied to an employee together with its audit entry
+type statusChange struct {
+	updates map[string]interface{}
+	action  string
+	detail  string
+	// revokeSessions logs the employee out of every device once the change is saved
+	revokeSessions bool
+	// removeSchedulesAfter (YYYY-MM-DD) removes the schedules after that date without attendance
+	removeSchedulesAfter string
+}
+
+// findTargetEmployee loads the employee from the :id parameter, admins cannot change their own status
+func findTargetEmployee(c *gin.Context) (models.Employee, bool) {
+	var employee models.Employee
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid employee ID",
+		})
+		return employee, false
+	}
+
+	if id == c.GetInt("employeeId") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "You cannot change the status of your own account",
+		})
+		return employee, false
+	}
+
+	if err := models.DB.First(&employee, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return employee, false
+	}
+
+	allowed, err := models.CanManageEmployee(models.DB, c.GetInt("employeeId"), employee.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check permissions: " + err.Error(),
+		})
+		return employee, false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot manage this employee, their role grants permissions you do not have",
+		})
+		return employee, false
+	}
+	return employee, true
+}
+
+// applyStatusChange saves the change, the removal of schedules and its audit entry in one transaction.
+// It returns how many schedules were removed.
+func applyStatusChange(c *gin.Context, employee *models.Employee, change statusChange) (int, error) {
+	actorID := c.GetInt("employeeId")
+	change.updates["status_changed_by"] = actorID
+	change.updates["status_changed_at"] = time.Now()
+
+	removed := 0
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(employee).Updates(change.updates).Error; err != nil {
+			return err
+		}
+		if change.removeSchedulesAfter != "" {
+			count, err := removeSchedulesAfter(tx, employee, change.removeSchedulesAfter, actorID)
+			if err != nil {
+				return err
+			}
+			removed = count
+		}
+		return models.RecordAudit(tx, models.AuditLog{
+			Action:     change.action,
+			ActorID:    &actorID,
+			EmployeeID: &employee.Id,
+			IPAddress:  c.ClientIP(),
+			Detail:     change.detail,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if change.revokeSessions {
+		if err := utils.RevokeAllSessions(employee.Id); err != nil {
+			return removed, err
+		}
+	}
+	return removed, models.DB.Preload("Position.Department").First(employee, employee.Id).Error
+}
+
+// removeSchedulesAfter deletes the schedules of the employee after the given date (YYYY-MM-DD) so coverage
+// and calendar feeds stop counting them. Schedules with attendance are kept, removing a published
+// schedule is recorded as a change.
+func removeSchedulesAfter(tx *gorm.DB, employee *models.Employee, date string, actorID int) (int, error) {
+	var position models.Position
+	if err := tx.First(&position, employee.PositionId).Error; err != nil {
+		return 0, err
+	}
+	var schedules []models.Schedule
+	if err := tx.Where("employee_id = ? AND date_schedule > ?", employee.Id, date).
+		Where("NOT EXISTS (SELECT 1 FROM attendances WHERE attendances.schedule_id = schedules.id)").
+		Find(&schedules).Error; err != nil {
+		return 0, err
+	}
+	for _, schedule := range schedules {
+		if err := tx.Delete(&schedule).Error; err != nil {
+			return 0, err
+		}
+		if schedule.Draft {
+			continue
+		}
+		change := models.NewScheduleChange(schedule, position.DepartmentId, models.ScheduleChangeDeleted, uint(actorID))
+		if err := models.RecordScheduleChange(tx, change); err != nil {
+			return 0, err
+		}
+	}
+	return len(schedules), nil
+}
+
+func formatEmployment(employee models.Employee) gin.H {
+	var terminationDate *string
+	if employee.TerminationDate != nil {
+		date := *employee.TerminationDate
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		if t, err := time.Parse("2006-01-02", date); err == nil {
+			formatted := t.Format("02-01-2006")
+			terminationDate = &formatted
+		}
+	}
+
+	return gin.H{
+		"id":                employee.Id,
+		"name":              employee.Name,
+		"email":             employee.Email,
+		"position":          employee.Position.PositionName,
+		"department":        employee.Position.Department.DepartmentName,
+		"status":            employee.Status,
+		"termination_date":  terminationDate,
+		"status_reason":     employee.StatusReason,
+		"status_changed_by": employee.StatusChangedBy,
+		"status_changed_at": employee.StatusChangedAt,
+	}
+}
@@ -0,0 +1,15 @@
+package employment
+
+type DeactivateEmployeeInput struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type TerminateEmployeeInput struct {
+	// EffectiveDate (DD-MM-YYYY) is the last working day
+	EffectiveDate string `json:"effective_date" binding:"required"`
+	Reason        string `json:"reason" binding:"required,max=255"`
+}
+
+type ReactivateEmployeeInput struct {
+	Note string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,62 @@
+package employment
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ReactivateEmployee gives a deactivated or terminated employee access again, e.g. on rehire (admin)
+func ReactivateEmployee(c *gin.Context) {
+	employee, ok := findTargetEmployee(c)
+	if !ok {
+		return
+	}
+
+	// body is optional
+	var input ReactivateEmployeeInput
+	if err := c.ShouldBindJSON(&input); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if employee.Status != models.EmployeeStatusDisabled && employee.Status != models.EmployeeStatusTerminated {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only deactivated or terminated employees can be reactivated",
+		})
+		return
+	}
+
+	detail := "Reactivated from " + employee.Status
+	if input.Note != "" {
+		detail += ": " + input.Note
+	}
+
+	_, err := applyStatusChange(c, &employee, statusChange{
+		updates: map[string]interface{}{
+			"status":           models.EmployeeStatusActive,
+			"termination_date": nil,
+			"status_reason":    input.Note,
+		},
+		action: models.AuditEmployeeReactivate,
+		detail: detail,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to reactivate employee: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  employee.Name + " has been reactivated",
+		"employee": formatEmployment(employee),
+	})
+}
@@ -0,0 +1,90 @@
+package employment
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// TerminateEmployee ends the employment on the effective date. The employee keeps access until
+// that day, a date in the past ends access immediately (admin).
+func TerminateEmployee(c *gin.Context) {
+	employee, ok := findTargetEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input TerminateEmployeeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	effectiveDate, err := time.Parse("02-01-2006", input.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	terminationDate := effectiveDate.Format("2006-01-02")
+
+	switch employee.Status {
+	case models.EmployeeStatusActive, models.EmployeeStatusDisabled, models.EmployeeStatusTerminated:
+	default:
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only approved employees can be terminated",
+		})
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	removed, err := applyStatusChange(c, &employee, statusChange{
+		updates: map[string]interface{}{
+			"status":           models.EmployeeStatusTerminated,
+			"termination_date": terminationDate,
+			"status_reason":    input.Reason,
+		},
+		action:               models.AuditEmployeeTerminate,
+		detail:               "Terminated effective " + input.EffectiveDate + ": " + input.Reason,
+		revokeSessions:       terminationDate < today,
+		removeSchedulesAfter: terminationDate,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to terminate employee: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  employee.Name + " has been terminated effective " + input.EffectiveDate,
+		"employee": formatEmployment(employee),
+		// schedules after the last working day are removed, the department has to fill the gaps
+		"removed_schedules": removed,
+	})
+}
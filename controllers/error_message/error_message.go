@@ -16,6 +16,8 @@ func GetErrorMsg(fe validator.FieldError) string {
 		return "Invalid email format"
 	case "min":
 		return "Minimum length is " + fe.Param()
+	case "oneof":
+		return "Must be one of: " + fe.Param()
 	}
 	return "Unknown Error"
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package errormessage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+)
+
+// GetUploadErrorMsg maps an error of utils.SaveImage to a response status and message
+func GetUploadErrorMsg(err error) (int, string) {
+	switch {
+	case errors.Is(err, utils.ErrUploadTooLarge):
+		return http.StatusBadRequest, fmt.Sprintf("File too large (max %dMB)", config.App.Upload.MaxSizeMB)
+	case errors.Is(err, utils.ErrUploadType):
+		return http.StatusBadRequest, "Only JPG, JPEG, and PNG files are allowed"
+	default:
+		return http.StatusInternalServerError, "Failed to save file"
+	}
+}
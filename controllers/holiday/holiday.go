@@ -0,0 +1,255 @@
+package holiday
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// maxHolidayRangeDays limits the range of GET /api/holidays
+const maxHolidayRangeDays = 731
+
+// bindJSON binds the body into input, answering 400 with the validation errors on failure
+func bindJSON(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return false
+	}
+	return true
+}
+
+// findCalendar loads the :id calendar, answering 404 when it does not exist
+func findCalendar(c *gin.Context) (models.HolidayCalendar, bool) {
+	var calendar models.HolidayCalendar
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&calendar).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Holiday calendar not found",
+		})
+		return calendar, false
+	}
+	return calendar, true
+}
+
+// ListHolidays returns the holidays of the active calendars between ?from= and ?to= (DD-MM-YYYY),
+// recurring holidays are repeated on every year. Defaults to the current year.
+func ListHolidays(c *gin.Context) {
+	now := time.Now()
+	from := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), 12, 31, 0, 0, 0, 0, time.UTC)
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid from format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid to format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		to = parsed
+	}
+	if to.Before(from) || to.Sub(from).Hours()/24 > maxHolidayRangeDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "to must be on or after from and the range at most two years",
+		})
+		return
+	}
+
+	index, err := models.LoadHolidays(models.DB, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve holidays: " + err.Error(),
+		})
+		return
+	}
+
+	holidays := make([]gin.H, 0, len(index))
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		if names, ok := index[key]; ok {
+			holidays = append(holidays, gin.H{
+				"date":  day.Format("02-01-2006"),
+				"names": names,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Holidays retrieved successfully",
+		"holidays": holidays,
+	})
+}
+
+// CreateHoliday adds a holiday to the calendar
+func CreateHoliday(c *gin.Context) {
+	calendar, ok := findCalendar(c)
+	if !ok {
+		return
+	}
+
+	var input HolidayInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	date, err := time.Parse("02-01-2006", input.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+
+	holiday := models.Holiday{
+		CalendarID: calendar.ID,
+		Name:       input.Name,
+		Date:       date.Format("2006-01-02"),
+		Recurring:  input.Recurring,
+	}
+	if err := models.DB.Create(&holiday).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create holiday: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": "Holiday created successfully",
+		"holiday": formatHoliday(holiday),
+	})
+}
+
+// UpdateHoliday changes a holiday of the calendar
+func UpdateHoliday(c *gin.Context) {
+	holiday, ok := findCalendarHoliday(c)
+	if !ok {
+		return
+	}
+
+	var input HolidayInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	date, err := time.Parse("02-01-2006", input.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+
+	holiday.Name, holiday.Date, holiday.Recurring = input.Name, date.Format("2006-01-02"), input.Recurring
+	if err := models.DB.Save(&holiday).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update holiday: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Holiday updated successfully",
+		"holiday": formatHoliday(holiday),
+	})
+}
+
+// DeleteHoliday removes a holiday from the calendar, attendance already marked keeps its flag
+func DeleteHoliday(c *gin.Context) {
+	holiday, ok := findCalendarHoliday(c)
+	if !ok {
+		return
+	}
+
+	if err := models.DB.Delete(&holiday).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete holiday: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Holiday deleted successfully",
+	})
+}
+
+// findCalendarHoliday loads the :holidayId holiday of the :id calendar
+func findCalendarHoliday(c *gin.Context) (models.Holiday, bool) {
+	var holiday models.Holiday
+	err := models.DB.Where("id = ? AND calendar_id = ?", c.Param("holidayId"), c.Param("id")).First(&holiday).Error
+	if err != nil {
+		status, message := http.StatusInternalServerError, "Failed to retrieve holiday: "+err.Error()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			status, message = http.StatusNotFound, "Holiday not found"
+		}
+		c.JSON(status, gin.H{
+			"error":   true,
+			"message": message,
+		})
+		return holiday, false
+	}
+	return holiday, true
+}
+
+// formatDate turns YYYY-MM-DD into the DD-MM-YYYY format used in responses
+func formatDate(date string) string {
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t.Format("02-01-2006")
+	}
+	return date
+}
+
+func formatHoliday(holiday models.Holiday) gin.H {
+	return gin.H{
+		"id":          holiday.ID,
+		"calendar_id": holiday.CalendarID,
+		"name":        holiday.Name,
+		"date":        formatDate(holiday.Date),
+		"recurring":   holiday.Recurring,
+		"uid":         holiday.UID,
+	}
+}
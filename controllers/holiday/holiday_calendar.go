@@ -0,0 +1,169 @@
+package holiday
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListHolidayCalendars returns every calendar with its number of holidays
+func ListHolidayCalendars(c *gin.Context) {
+	var calendars []models.HolidayCalendar
+	if err := models.DB.Order("name").Find(&calendars).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve holiday calendars: " + err.Error(),
+		})
+		return
+	}
+
+	type holidayCount struct {
+		CalendarID uint
+		Total      int
+	}
+	var counts []holidayCount
+	models.DB.Model(&models.Holiday{}).Select("calendar_id, COUNT(*) AS total").Group("calendar_id").Scan(&counts)
+	totals := make(map[uint]int, len(counts))
+	for _, count := range counts {
+		totals[count.CalendarID] = count.Total
+	}
+
+	data := make([]gin.H, 0, len(calendars))
+	for _, calendar := range calendars {
+		item := formatCalendar(calendar)
+		item["total_holidays"] = totals[calendar.ID]
+		data = append(data, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":     false,
+		"message":   "Holiday calendars retrieved successfully",
+		"calendars": data,
+	})
+}
+
+// GetHolidayCalendar returns a calendar with its holidays as stored, recurring ones once
+func GetHolidayCalendar(c *gin.Context) {
+	calendar, ok := findCalendar(c)
+	if !ok {
+		return
+	}
+
+	var holidays []models.Holiday
+	if err := models.DB.Where("calendar_id = ?", calendar.ID).Order("date, id").Find(&holidays).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve holidays: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(holidays))
+	for _, holiday := range holidays {
+		data = append(data, formatHoliday(holiday))
+	}
+	item := formatCalendar(calendar)
+	item["holidays"] = data
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Holiday calendar retrieved successfully",
+		"calendar": item,
+	})
+}
+
+// CreateHolidayCalendar adds a calendar
+func CreateHolidayCalendar(c *gin.Context) {
+	var input HolidayCalendarInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	calendar := models.HolidayCalendar{
+		Name:        input.Name,
+		Description: input.Description,
+		Active:      input.Active == nil || *input.Active,
+	}
+	if err := models.DB.Create(&calendar).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create holiday calendar: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":    false,
+		"message":  "Holiday calendar created successfully",
+		"calendar": formatCalendar(calendar),
+	})
+}
+
+// UpdateHolidayCalendar renames a calendar or switches it on or off
+func UpdateHolidayCalendar(c *gin.Context) {
+	calendar, ok := findCalendar(c)
+	if !ok {
+		return
+	}
+
+	var input HolidayCalendarInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	calendar.Name, calendar.Description = input.Name, input.Description
+	if input.Active != nil {
+		calendar.Active = *input.Active
+	}
+	if err := models.DB.Save(&calendar).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update holiday calendar: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Holiday calendar updated successfully",
+		"calendar": formatCalendar(calendar),
+	})
+}
+
+// DeleteHolidayCalendar removes a calendar with its holidays
+func DeleteHolidayCalendar(c *gin.Context) {
+	calendar, ok := findCalendar(c)
+	if !ok {
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id = ?", calendar.ID).Delete(&models.Holiday{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&calendar).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete holiday calendar: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Holiday calendar deleted successfully",
+	})
+}
+
+func formatCalendar(calendar models.HolidayCalendar) gin.H {
+	return gin.H{
+		"id":          calendar.ID,
+		"name":        calendar.Name,
+		"description": calendar.Description,
+		"active":      calendar.Active,
+	}
+}
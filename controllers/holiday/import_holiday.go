@@ -0,0 +1,130 @@
+package holiday
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxICalendarSize limits the size of an imported file
+	maxICalendarSize = 1 << 20
+	// maxImportedEventDays limits the days created for one multi-day event
+	maxImportedEventDays = 31
+)
+
+// ImportHolidays reads an iCalendar file (multipart field "file", or the raw request body) into the
+// calendar. Events are matched on UID and date, so importing the same file again updates the holidays.
+func ImportHolidays(c *gin.Context) {
+	calendar, ok := findCalendar(c)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		opened, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Failed to read file: " + err.Error(),
+			})
+			return
+		}
+		defer opened.Close()
+		reader = opened
+	} else {
+		reader = c.Request.Body
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxICalendarSize+1))
+	if err != nil || len(data) > maxICalendarSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "The file must be an iCalendar file of at most 1 MB",
+		})
+		return
+	}
+
+	events, err := utils.ParseICalendar(strings.NewReader(string(data)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "The file is not a valid iCalendar file",
+		})
+		return
+	}
+
+	summary := map[string]int{"created": 0, "updated": 0, "skipped": 0}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		for _, event := range events {
+			if strings.TrimSpace(event.Summary) == "" {
+				summary["skipped"]++
+				continue
+			}
+
+			days := int(event.End.Sub(event.Start).Hours() / 24)
+			if event.Yearly || days < 1 {
+				days = 1
+			}
+			if days > maxImportedEventDays {
+				summary["skipped"]++
+				continue
+			}
+
+			for i := 0; i < days; i++ {
+				result, err := importHoliday(tx, calendar.ID, event, event.Start.AddDate(0, 0, i).Format("2006-01-02"))
+				if err != nil {
+					return err
+				}
+				summary[result]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to import holidays: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Holidays imported successfully",
+		"summary": summary,
+	})
+}
+
+// importHoliday creates or updates the holiday of the event on the date
+func importHoliday(tx *gorm.DB, calendarID uint, event utils.ICalEvent, date string) (string, error) {
+	name := utils.Truncate(event.Summary, 255)
+	uid := utils.Truncate(event.UID, 255)
+
+	if uid != "" {
+		var existing models.Holiday
+		err := tx.Where("calendar_id = ? AND uid = ? AND date = ?", calendarID, uid, date).First(&existing).Error
+		if err == nil {
+			existing.Name, existing.Recurring = name, event.Yearly
+			return "updated", tx.Save(&existing).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+
+	return "created", tx.Create(&models.Holiday{
+		CalendarID: calendarID,
+		Name:       name,
+		Date:       date,
+		Recurring:  event.Yearly,
+		UID:        uid,
+	}).Error
+}
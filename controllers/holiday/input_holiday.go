@@ -0,0 +1,15 @@
+package holiday
+
+type HolidayCalendarInput struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=255"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+type HolidayInput struct {
+	Name string `json:"name" binding:"required,max=255"`
+	// Date is DD-MM-YYYY, for a recurring holiday the first year it applies
+	Date      string `json:"date" binding:"required"`
+	Recurring bool   `json:"recurring"`
+}
@@ -0,0 +1,11 @@
+package kiosk
+
+type KioskInput struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// DepartmentID limits the kiosk to one department
+	DepartmentID *int `json:"department_id"`
+	// WorkLocationID is where the kiosk stands
+	WorkLocationID *uint `json:"work_location_id"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
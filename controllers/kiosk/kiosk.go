@@ -0,0 +1,302 @@
+package kiosk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ListKiosks returns every registered kiosk
+func ListKiosks(c *gin.Context) {
+	var kiosks []models.KioskDevice
+	if err := models.DB.Order("name").Find(&kiosks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve kiosks: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosks retrieved successfully",
+		"kiosks":  kiosks,
+	})
+}
+
+// CreateKiosk registers a kiosk and returns its device token, which is only shown once
+func CreateKiosk(c *gin.Context) {
+	var input KioskInput
+	if !bindJSON(c, &input) || !validReferences(c, input) {
+		return
+	}
+
+	token, err := utils.NewKioskToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create kiosk token: " + err.Error(),
+		})
+		return
+	}
+	secret, err := utils.NewKioskSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create kiosk secret: " + err.Error(),
+		})
+		return
+	}
+
+	kiosk := models.KioskDevice{
+		Name:            input.Name,
+		DepartmentID:    input.DepartmentID,
+		WorkLocationID:  input.WorkLocationID,
+		TokenHash:       utils.HashToken(token),
+		Secret:          secret,
+		SecretRotatedAt: time.Now(),
+		Active:          input.Active == nil || *input.Active,
+		CreatedBy:       c.GetInt("employeeId"),
+	}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&kiosk).Error; err != nil {
+			return err
+		}
+		return recordKioskAudit(tx, c, models.AuditKioskRegister, kiosk)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create kiosk: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": "Kiosk registered, set the token on the kiosk. It is only shown once.",
+		"kiosk":   kiosk,
+		"token":   token,
+	})
+}
+
+// UpdateKiosk renames, moves or switches a kiosk on or off
+func UpdateKiosk(c *gin.Context) {
+	kiosk, ok := findKiosk(c)
+	if !ok {
+		return
+	}
+
+	var input KioskInput
+	if !bindJSON(c, &input) || !validReferences(c, input) {
+		return
+	}
+
+	kiosk.Name, kiosk.DepartmentID, kiosk.WorkLocationID = input.Name, input.DepartmentID, input.WorkLocationID
+	if input.Active != nil {
+		kiosk.Active = *input.Active
+	}
+	if err := models.DB.Save(&kiosk).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update kiosk: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosk updated successfully",
+		"kiosk":   kiosk,
+	})
+}
+
+// DeleteKiosk removes a kiosk, its token and codes stop working
+func DeleteKiosk(c *gin.Context) {
+	kiosk, ok := findKiosk(c)
+	if !ok {
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("kiosk_id = ?", kiosk.ID).Delete(&models.KioskCodeUse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&kiosk).Error; err != nil {
+			return err
+		}
+		return recordKioskAudit(tx, c, models.AuditKioskDelete, kiosk)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete kiosk: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosk deleted successfully",
+	})
+}
+
+// RotateKioskSecret replaces the secret the codes of a kiosk are signed with, codes already shown stop working
+func RotateKioskSecret(c *gin.Context) {
+	kiosk, ok := findKiosk(c)
+	if !ok {
+		return
+	}
+
+	secret, err := utils.NewKioskSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create kiosk secret: " + err.Error(),
+		})
+		return
+	}
+	kiosk.Secret, kiosk.SecretRotatedAt = secret, time.Now()
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&kiosk).Updates(map[string]interface{}{
+			"secret":            kiosk.Secret,
+			"secret_rotated_at": kiosk.SecretRotatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return recordKioskAudit(tx, c, models.AuditKioskRotateSecret, kiosk)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to rotate kiosk secret: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosk secret rotated successfully",
+		"kiosk":   kiosk,
+	})
+}
+
+// ReissueKioskToken gives a kiosk a new device token, e.g. when the terminal is replaced or lost.
+// The old token stops working and the new one is only shown once.
+func ReissueKioskToken(c *gin.Context) {
+	kiosk, ok := findKiosk(c)
+	if !ok {
+		return
+	}
+
+	token, err := utils.NewKioskToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create kiosk token: " + err.Error(),
+		})
+		return
+	}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&kiosk).Update("token_hash", utils.HashToken(token)).Error; err != nil {
+			return err
+		}
+		return recordKioskAudit(tx, c, models.AuditKioskReissueToken, kiosk)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to reissue kiosk token: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosk token reissued, set it on the kiosk. It is only shown once.",
+		"kiosk":   kiosk,
+		"token":   token,
+	})
+}
+
+func recordKioskAudit(tx *gorm.DB, c *gin.Context, action string, kiosk models.KioskDevice) error {
+	actorID := c.GetInt("employeeId")
+	return models.RecordAudit(tx, models.AuditLog{
+		Action:    action,
+		ActorID:   &actorID,
+		IPAddress: c.ClientIP(),
+		Detail:    fmt.Sprintf("kiosk %d (%s)", kiosk.ID, kiosk.Name),
+	})
+}
+
+// validReferences checks that the department and work location of the input exist
+func validReferences(c *gin.Context, input KioskInput) bool {
+	if input.DepartmentID != nil {
+		var count int64
+		models.DB.Model(&models.Department{}).Where("id = ?", *input.DepartmentID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Department not found",
+			})
+			return false
+		}
+	}
+	if input.WorkLocationID != nil {
+		var count int64
+		models.DB.Model(&models.WorkLocation{}).Where("id = ?", *input.WorkLocationID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Work location not found",
+			})
+			return false
+		}
+	}
+	return true
+}
+
+// bindJSON binds the request body, answering 400 with the validation errors when it is invalid
+func bindJSON(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return false
+	}
+	return true
+}
+
+// findKiosk loads the :id kiosk, answering 404 when it does not exist
+func findKiosk(c *gin.Context) (models.KioskDevice, bool) {
+	var kiosk models.KioskDevice
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&kiosk).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Kiosk not found",
+		})
+		return kiosk, false
+	}
+	return kiosk, true
+}
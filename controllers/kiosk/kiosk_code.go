@@ -0,0 +1,68 @@
+package kiosk
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetKioskCode returns the code the kiosk shows as a QR code right now. The kiosk authenticates with its
+// device token in the X-Kiosk-Token header and should ask again when the code expires.
+func GetKioskCode(c *gin.Context) {
+	token := c.GetHeader("X-Kiosk-Token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "X-Kiosk-Token header is required",
+		})
+		return
+	}
+
+	var kiosk models.KioskDevice
+	if err := models.DB.Where("token_hash = ?", utils.HashToken(token)).First(&kiosk).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Invalid kiosk token",
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to load kiosk: " + err.Error(),
+			})
+		}
+		return
+	}
+	if !kiosk.Active {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "Kiosk is not active",
+		})
+		return
+	}
+
+	now := utils.Now()
+	models.DB.Model(&kiosk).Update("last_seen_at", now)
+
+	period := config.App.Attendance.KioskCodePeriod.Duration
+	counter := utils.KioskCounter(now, period)
+	expiresAt := time.Unix((counter+1)*int64(period/time.Second), 0)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Kiosk code generated successfully",
+		"kiosk": gin.H{
+			"id":   kiosk.ID,
+			"name": kiosk.Name,
+		},
+		"code":       utils.KioskCode(kiosk.ID, counter, kiosk.Secret),
+		"period":     int(period / time.Second),
+		"expires_at": expiresAt.Format(time.RFC3339),
+	})
+}
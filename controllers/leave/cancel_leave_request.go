@@ -0,0 +1,83 @@
+package leave
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CancelLeaveRequest lets the employee withdraw a pending request, or an approved one that has not
+// started yet. Approved days go back to the balance and the schedules are restored.
+func CancelLeaveRequest(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findLeaveRequest(c)
+	if !ok {
+		return
+	}
+
+	if request.EmployeeID != uint(employee.Id) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Leave request not found",
+		})
+		return
+	}
+
+	switch request.Status {
+	case models.LeaveStatusPending:
+	case models.LeaveStatusApproved:
+		if dbDate(request.StartDate) <= time.Now().Format("2006-01-02") {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": "Leave that has already started cannot be cancelled",
+			})
+			return
+		}
+	default:
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "The leave request is already " + request.Status,
+		})
+		return
+	}
+
+	wasApproved := request.Status == models.LeaveStatusApproved
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.LeaveRequest{}).
+			Where("id = ? AND status = ?", request.ID, request.Status).
+			Update("status", models.LeaveStatusCancelled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errLeaveStateChanged
+		}
+		if !wasApproved {
+			return nil
+		}
+
+		if request.LeaveType.TracksBalance {
+			if err := refundBalance(tx, request); err != nil {
+				return err
+			}
+		}
+		return unmarkLeaveSchedules(tx, request)
+	})
+	if err != nil {
+		respondDecisionError(c, err, "Failed to cancel leave request")
+		return
+	}
+	request.Status = models.LeaveStatusCancelled
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":         false,
+		"message":       "Leave request cancelled",
+		"leave_request": formatLeaveRequest(request),
+	})
+}
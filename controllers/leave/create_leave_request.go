@@ -0,0 +1,175 @@
+package leave
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxLeaveDays limits the length of a single request
+const maxLeaveDays = 180
+
+// CreateLeaveRequest files a leave request of the logged-in employee for the department manager to approve
+func CreateLeaveRequest(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input CreateLeaveRequestInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	startDate, err := time.Parse("02-01-2006", input.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid start_date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	endDate, err := time.Parse("02-01-2006", input.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid end_date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	span := int(endDate.Sub(startDate).Hours()/24) + 1
+	if span < 1 || span > maxLeaveDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": fmt.Sprintf("end_date must be on or after start_date and the leave at most %d days", maxLeaveDays),
+		})
+		return
+	}
+	// balances are yearly, leave over new year is requested as two requests
+	if startDate.Year() != endDate.Year() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "A leave request cannot span two years, split it at the end of the year",
+		})
+		return
+	}
+
+	var leaveType models.LeaveType
+	if err := models.DB.First(&leaveType, input.LeaveTypeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Leave type not found",
+		})
+		return
+	}
+
+	start, end := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+
+	var overlapping int64
+	if err := models.DB.Model(&models.LeaveRequest{}).
+		Where("employee_id = ? AND status IN ? AND start_date <= ? AND end_date >= ?",
+			employee.Id, []string{models.LeaveStatusPending, models.LeaveStatusApproved}, end, start).
+		Count(&overlapping).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check leave requests: " + err.Error(),
+		})
+		return
+	}
+	if overlapping > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "You already have a leave request overlapping these dates",
+		})
+		return
+	}
+
+	attended, err := attendanceDuring(models.DB, uint(employee.Id), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check attendance: " + err.Error(),
+		})
+		return
+	}
+	if attended != "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "You already clocked in on " + formatDate(attended),
+		})
+		return
+	}
+
+	// only the days the employee is scheduled to work count as leave
+	days, err := scheduledDays(models.DB, uint(employee.Id), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check schedules: " + err.Error(),
+		})
+		return
+	}
+	if days == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "You are not scheduled to work between these dates",
+		})
+		return
+	}
+
+	if leaveType.TracksBalance {
+		balance, err := findBalance(models.DB, uint(employee.Id), leaveType, startDate.Year())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to retrieve leave balance: " + err.Error(),
+			})
+			return
+		}
+		pending, err := pendingDays(models.DB, uint(employee.Id), leaveType.ID, startDate.Year())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to retrieve pending leave: " + err.Error(),
+			})
+			return
+		}
+		if available := balance.Remaining() - pending; days > available {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     true,
+				"message":   fmt.Sprintf("Insufficient %s balance: %d days requested, %d available", leaveType.Name, days, available),
+				"available": available,
+			})
+			return
+		}
+	}
+
+	request := models.LeaveRequest{
+		EmployeeID:   uint(employee.Id),
+		DepartmentID: employee.Position.DepartmentId,
+		LeaveTypeID:  leaveType.ID,
+		StartDate:    start,
+		EndDate:      end,
+		Days:         days,
+		Reason:       input.Reason,
+		Status:       models.LeaveStatusPending,
+	}
+	if err := models.DB.Create(&request).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create leave request: " + err.Error(),
+		})
+		return
+	}
+
+	request.Employee, request.LeaveType = employee, leaveType
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":         false,
+		"message":       "Leave request submitted, waiting for approval",
+		"leave_request": formatLeaveRequest(request),
+	})
+}
@@ -0,0 +1,173 @@
+package leave
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errLeaveStateChanged means another request decided on the leave between reading and updating it
+var errLeaveStateChanged = errors.New("the leave request was changed in the meantime, reload it and try again")
+
+// findDepartmentLeaveRequest loads the :id pending request for a manager of its department
+func findDepartmentLeaveRequest(c *gin.Context, manager models.Employee) (models.LeaveRequest, bool) {
+	request, ok := findLeaveRequest(c)
+	if !ok {
+		return request, false
+	}
+	if request.DepartmentID != manager.Position.DepartmentId {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You can only manage leave requests of your department",
+		})
+		return request, false
+	}
+	if request.EmployeeID == uint(manager.Id) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot decide on your own leave request",
+		})
+		return request, false
+	}
+	if request.Status != models.LeaveStatusPending {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "The leave request is already " + request.Status,
+		})
+		return request, false
+	}
+	return request, true
+}
+
+// decideLeave moves a pending request to the given status, only while it is still pending
+func decideLeave(tx *gorm.DB, request *models.LeaveRequest, status string, managerID uint, note string) error {
+	now := time.Now()
+	result := tx.Model(&models.LeaveRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.LeaveStatusPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"decided_by":    managerID,
+			"decided_at":    now,
+			"decision_note": note,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errLeaveStateChanged
+	}
+
+	request.Status, request.DecidedBy, request.DecidedAt, request.DecisionNote = status, &managerID, &now, note
+	return nil
+}
+
+// ApproveLeaveRequest approves a pending request: the days are deducted from the balance and the
+// schedules during the leave are marked, all in one transaction
+func ApproveLeaveRequest(c *gin.Context) {
+	manager, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findDepartmentLeaveRequest(c, manager)
+	if !ok {
+		return
+	}
+	note, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+
+	attended, err := attendanceDuring(models.DB, request.EmployeeID, dbDate(request.StartDate), dbDate(request.EndDate))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check attendance: " + err.Error(),
+		})
+		return
+	}
+	if attended != "" {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": request.Employee.Name + " already clocked in on " + formatDate(attended),
+		})
+		return
+	}
+
+	var marked int64
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := decideLeave(tx, &request, models.LeaveStatusApproved, uint(manager.Id), note); err != nil {
+			return err
+		}
+		if request.LeaveType.TracksBalance {
+			if err := deductBalance(tx, request); err != nil {
+				return err
+			}
+		}
+		var err error
+		marked, err = markLeaveSchedules(tx, request)
+		return err
+	})
+	if err != nil {
+		respondDecisionError(c, err, "Failed to approve leave request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":             false,
+		"message":           "Leave request approved",
+		"leave_request":     formatLeaveRequest(request),
+		"schedules_updated": marked,
+	})
+}
+
+// RejectLeaveRequest rejects a pending request, the balance is not touched
+func RejectLeaveRequest(c *gin.Context) {
+	manager, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findDepartmentLeaveRequest(c, manager)
+	if !ok {
+		return
+	}
+	note, ok := bindDecision(c)
+	if !ok {
+		return
+	}
+
+	if err := decideLeave(models.DB, &request, models.LeaveStatusRejected, uint(manager.Id), note); err != nil {
+		respondDecisionError(c, err, "Failed to reject leave request")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":         false,
+		"message":       "Leave request rejected",
+		"leave_request": formatLeaveRequest(request),
+	})
+}
+
+// respondDecisionError maps errors of a decision to a response
+func respondDecisionError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, errLeaveStateChanged):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+	case errors.Is(err, errInsufficientBalance):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "The employee's leave balance no longer covers this request",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": message + ": " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package leave
+
+type LeaveTypeInput struct {
+	Code          string `json:"code" binding:"required,max=30"`
+	Name          string `json:"name" binding:"required,max=100"`
+	DefaultDays   int    `json:"default_days" binding:"min=0"`
+	TracksBalance bool   `json:"tracks_balance"`
+	Paid          bool   `json:"paid"`
+}
+
+type LeaveBalanceInput struct {
+	LeaveTypeID uint `json:"leave_type_id" binding:"required"`
+	Year        int  `json:"year" binding:"required,min=2000,max=2100"`
+	Entitled    int  `json:"entitled" binding:"min=0"`
+}
+
+type CreateLeaveRequestInput struct {
+	LeaveTypeID uint   `json:"leave_type_id" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date" binding:"required"`
+	Reason      string `json:"reason" binding:"max=255"`
+}
+
+type LeaveDecisionInput struct {
+	Note string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,244 @@
+package leave
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// errInsufficientBalance means the balance no longer covers the request
+var errInsufficientBalance = errors.New("insufficient leave balance")
+
+// bindJSON binds the body into input, answering 400 with the validation errors on failure
+func bindJSON(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return false
+	}
+	return true
+}
+
+// bindDecision reads the optional note of a decision, the body may be empty
+func bindDecision(c *gin.Context) (string, bool) {
+	var input LeaveDecisionInput
+	if c.Request.ContentLength == 0 {
+		return "", true
+	}
+	if !bindJSON(c, &input) {
+		return "", false
+	}
+	return input.Note, true
+}
+
+// loadCurrentEmployee returns the authenticated employee with their position
+func loadCurrentEmployee(c *gin.Context) (models.Employee, bool) {
+	var employee models.Employee
+
+	employeeID, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return employee, false
+	}
+
+	if err := models.DB.Preload("Position").First(&employee, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return employee, false
+	}
+	return employee, true
+}
+
+// findLeaveRequest loads the :id request, answering 404 when it does not exist
+func findLeaveRequest(c *gin.Context) (models.LeaveRequest, bool) {
+	var request models.LeaveRequest
+	if err := models.DB.Preload("Employee").Preload("LeaveType").Where("id = ?", c.Param("id")).First(&request).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Leave request not found",
+		})
+		return request, false
+	}
+	return request, true
+}
+
+// findBalance returns the balance of the employee for the year, creating it from the
+// default entitlement of the leave type on first use
+func findBalance(tx *gorm.DB, employeeID uint, leaveType models.LeaveType, year int) (models.LeaveBalance, error) {
+	var balance models.LeaveBalance
+	err := tx.Where(models.LeaveBalance{EmployeeID: employeeID, LeaveTypeID: leaveType.ID, Year: year}).
+		Attrs(models.LeaveBalance{Entitled: leaveType.DefaultDays}).
+		FirstOrCreate(&balance).Error
+	return balance, err
+}
+
+// pendingDays sums the days of the pending requests of the employee for the leave type in the year
+func pendingDays(tx *gorm.DB, employeeID, leaveTypeID uint, year int) (int, error) {
+	var days int
+	err := tx.Model(&models.LeaveRequest{}).
+		Select("COALESCE(SUM(days), 0)").
+		Where("employee_id = ? AND leave_type_id = ? AND status = ? AND start_date BETWEEN ? AND ?",
+			employeeID, leaveTypeID, models.LeaveStatusPending,
+			time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+			time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).Format("2006-01-02")).
+		Scan(&days).Error
+	return days, err
+}
+
+// deductBalance takes the days of the request from the balance. The update only applies while
+// the balance still covers the request, so concurrent approvals cannot overdraw it.
+func deductBalance(tx *gorm.DB, request models.LeaveRequest) error {
+	balance, err := findBalance(tx, request.EmployeeID, request.LeaveType, requestYear(request))
+	if err != nil {
+		return err
+	}
+	result := tx.Model(&models.LeaveBalance{}).
+		Where("id = ? AND entitled - used >= ?", balance.ID, request.Days).
+		Update("used", gorm.Expr("used + ?", request.Days))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errInsufficientBalance
+	}
+	return nil
+}
+
+// refundBalance gives the days of an approved request back to the balance
+func refundBalance(tx *gorm.DB, request models.LeaveRequest) error {
+	balance, err := findBalance(tx, request.EmployeeID, request.LeaveType, requestYear(request))
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.LeaveBalance{}).
+		Where("id = ?", balance.ID).
+		Update("used", gorm.Expr("CASE WHEN used >= ? THEN used - ? ELSE 0 END", request.Days, request.Days)).Error
+}
+
+// markLeaveSchedules flags the schedules of the employee during the leave, keeping their status so
+// unmarkLeaveSchedules can restore it
+func markLeaveSchedules(tx *gorm.DB, request models.LeaveRequest) (int64, error) {
+	during := tx.Model(&models.Schedule{}).
+		Where("employee_id = ? AND date_schedule BETWEEN ? AND ?", request.EmployeeID, dbDate(request.StartDate), dbDate(request.EndDate))
+	// separate statements, MySQL would copy the new status when both are set at once
+	if err := during.Session(&gorm.Session{}).Update("status_before_leave", gorm.Expr("status")).Error; err != nil {
+		return 0, err
+	}
+	result := during.Session(&gorm.Session{}).
+		Updates(map[string]interface{}{"status": models.ScheduleStatusLeave, "leave_request_id": request.ID})
+	return result.RowsAffected, result.Error
+}
+
+// unmarkLeaveSchedules puts the schedules flagged by the request back to the status they had before
+func unmarkLeaveSchedules(tx *gorm.DB, request models.LeaveRequest) error {
+	flagged := tx.Model(&models.Schedule{}).Where("leave_request_id = ?", request.ID)
+	if err := flagged.Session(&gorm.Session{}).
+		Update("status", gorm.Expr("COALESCE(NULLIF(status_before_leave, ''), ?)", models.ScheduleStatusPresent)).Error; err != nil {
+		return err
+	}
+	return flagged.Session(&gorm.Session{}).
+		Updates(map[string]interface{}{"status_before_leave": "", "leave_request_id": nil}).Error
+}
+
+// scheduledDays counts the dates from start to end (YYYY-MM-DD) on which the employee is scheduled,
+// days off within a leave are not taken from the balance
+func scheduledDays(tx *gorm.DB, employeeID uint, start, end string) (int, error) {
+	var count int64
+	err := tx.Model(&models.Schedule{}).
+		Where("employee_id = ? AND date_schedule BETWEEN ? AND ?", employeeID, start, end).
+		Distinct("date_schedule").
+		Count(&count).Error
+	return int(count), err
+}
+
+// attendanceDuring returns the first date in the range on which the employee already clocked in
+func attendanceDuring(tx *gorm.DB, employeeID uint, startDate, endDate string) (string, error) {
+	var attendance models.Attendance
+	err := tx.Joins("JOIN schedules ON schedules.id = attendances.schedule_id").
+		Where("schedules.employee_id = ? AND attendances.date BETWEEN ? AND ?", employeeID, startDate, endDate).
+		Order("attendances.date").
+		First(&attendance).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return dbDate(attendance.Date), err
+}
+
+// dbDate cuts dates read back as timestamps to YYYY-MM-DD
+func dbDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// formatDate turns YYYY-MM-DD into the DD-MM-YYYY format used in responses
+func formatDate(date string) string {
+	if t, err := time.Parse("2006-01-02", dbDate(date)); err == nil {
+		return t.Format("02-01-2006")
+	}
+	return date
+}
+
+func requestYear(request models.LeaveRequest) int {
+	t, _ := time.Parse("2006-01-02", dbDate(request.StartDate))
+	return t.Year()
+}
+
+func formatLeaveType(leaveType models.LeaveType) gin.H {
+	return gin.H{
+		"id":             leaveType.ID,
+		"code":           leaveType.Code,
+		"name":           leaveType.Name,
+		"default_days":   leaveType.DefaultDays,
+		"tracks_balance": leaveType.TracksBalance,
+		"paid":           leaveType.Paid,
+	}
+}
+
+func formatLeaveRequest(request models.LeaveRequest) gin.H {
+	var decidedAt interface{}
+	if request.DecidedAt != nil {
+		decidedAt = request.DecidedAt.Format(time.RFC3339)
+	}
+
+	return gin.H{
+		"id":            request.ID,
+		"employee":      gin.H{"id": request.Employee.Id, "name": request.Employee.Name},
+		"leave_type":    formatLeaveType(request.LeaveType),
+		"start_date":    formatDate(request.StartDate),
+		"end_date":      formatDate(request.EndDate),
+		"days":          request.Days,
+		"reason":        request.Reason,
+		"status":        request.Status,
+		"decided_by":    request.DecidedBy,
+		"decided_at":    decidedAt,
+		"decision_note": request.DecisionNote,
+		"created_at":    request.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,158 @@
+package leave
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyLeaveBalances returns the balances of the logged-in employee, ?year= defaults to the current year
+func GetMyLeaveBalances(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	respondBalances(c, employee)
+}
+
+// GetEmployeeLeaveBalances returns the balances of an employee
+func GetEmployeeLeaveBalances(c *gin.Context) {
+	var employee models.Employee
+	if err := models.DB.First(&employee, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+	respondBalances(c, employee)
+}
+
+// SetEmployeeLeaveBalance sets the yearly entitlement of an employee for a leave type
+func SetEmployeeLeaveBalance(c *gin.Context) {
+	var employee models.Employee
+	if err := models.DB.First(&employee, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	var input LeaveBalanceInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var leaveType models.LeaveType
+	if err := models.DB.First(&leaveType, input.LeaveTypeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Leave type not found",
+		})
+		return
+	}
+	if !leaveType.TracksBalance {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "This leave type has no balance",
+		})
+		return
+	}
+
+	balance, err := findBalance(models.DB, uint(employee.Id), leaveType, input.Year)
+	if err == nil {
+		err = models.DB.Model(&balance).Update("entitled", input.Entitled).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update leave balance: " + err.Error(),
+		})
+		return
+	}
+
+	pending, err := pendingDays(models.DB, uint(employee.Id), leaveType.ID, input.Year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve pending leave: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Leave balance updated successfully",
+		"balance": formatBalance(balance, leaveType, pending),
+	})
+}
+
+// respondBalances answers with the balances of every leave type that tracks one
+func respondBalances(c *gin.Context, employee models.Employee) {
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 2000 || parsed > 2100 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid year",
+			})
+			return
+		}
+		year = parsed
+	}
+
+	var leaveTypes []models.LeaveType
+	if err := models.DB.Where("tracks_balance = ?", true).Order("id").Find(&leaveTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve leave types: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		balance, err := findBalance(models.DB, uint(employee.Id), leaveType, year)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to retrieve leave balances: " + err.Error(),
+			})
+			return
+		}
+		pending, err := pendingDays(models.DB, uint(employee.Id), leaveType.ID, year)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to retrieve pending leave: " + err.Error(),
+			})
+			return
+		}
+		data = append(data, formatBalance(balance, leaveType, pending))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":       false,
+		"message":     "Leave balances retrieved successfully",
+		"employee_id": employee.Id,
+		"year":        year,
+		"balances":    data,
+	})
+}
+
+func formatBalance(balance models.LeaveBalance, leaveType models.LeaveType, pending int) gin.H {
+	return gin.H{
+		"leave_type": formatLeaveType(leaveType),
+		"year":       balance.Year,
+		"entitled":   balance.Entitled,
+		"used":       balance.Used,
+		"pending":    pending,
+		"remaining":  balance.Remaining(),
+		"available":  balance.Remaining() - pending,
+	}
+}
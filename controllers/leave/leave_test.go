@@ -0,0 +1,179 @@
+package leave
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// setupTestDB connects a fresh SQLite database
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	models.ConnectDatabase(cfg.Database)
+}
+
+// createEmployees creates active employees in one department and returns their ids
+func createEmployees(t *testing.T, names ...string) []int {
+	t.Helper()
+	department := models.Department{DepartmentName: "Housekeeping"}
+	if err := models.DB.Create(&department).Error; err != nil {
+		t.Fatalf("create department: %v", err)
+	}
+	position := models.Position{DepartmentId: department.Id, PositionName: "Room Attendant"}
+	if err := models.DB.Create(&position).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+	ids := make([]int, len(names))
+	for i, name := range names {
+		employee := models.Employee{PositionId: position.Id, Name: name, Email: name + "@example.com", Password: "-",
+			Phone: "1", Status: models.EmployeeStatusActive}
+		if err := models.DB.Create(&employee).Error; err != nil {
+			t.Fatalf("create employee: %v", err)
+		}
+		ids[i] = employee.Id
+	}
+	return ids
+}
+
+// createSchedules schedules the employee on the dates (YYYY-MM-DD) with the given status
+func createSchedules(t *testing.T, employeeID int, status string, dates ...string) {
+	t.Helper()
+	var shift models.Shift
+	models.DB.FirstOrCreate(&shift, models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"})
+	for _, date := range dates {
+		schedule := models.Schedule{EmployeeID: uint(employeeID), ShiftID: shift.ID, CreatedBy: uint(employeeID),
+			DateSchedule: date, Status: status}
+		if err := models.DB.Create(&schedule).Error; err != nil {
+			t.Fatalf("create schedule: %v", err)
+		}
+	}
+}
+
+// leaveTypeID returns the id of the seeded leave type with the code
+func leaveTypeID(t *testing.T, code string) uint {
+	t.Helper()
+	var leaveType models.LeaveType
+	if err := models.DB.Where("code = ?", code).First(&leaveType).Error; err != nil {
+		t.Fatalf("leave type %s: %v", code, err)
+	}
+	return leaveType.ID
+}
+
+// call runs the handler as the employee and returns the status and decoded response
+func call(t *testing.T, handler gin.HandlerFunc, employeeID int, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	router := gin.New()
+	router.Handle(method, "/leave-requests/*id", func(c *gin.Context) {
+		c.Set("employeeId", employeeID)
+		c.Params = gin.Params{{Key: "id", Value: c.Param("id")[1:]}}
+	}, handler)
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response %s: %v", w.Body, err)
+	}
+	return w.Code, response
+}
+
+func TestCreateLeaveRequestCountsScheduledDays(t *testing.T) {
+	cases := []struct {
+		name       string
+		leaveType  string
+		start, end string
+		wantStatus int
+		wantDays   float64
+	}{
+		{"days off are not counted", models.LeaveTypeAnnual, "01-03-2099", "07-03-2099", http.StatusCreated, 5},
+		{"no schedules in the range", models.LeaveTypeAnnual, "06-03-2099", "07-03-2099", http.StatusBadRequest, 0},
+		{"more scheduled days than the balance", models.LeaveTypeAnnual, "01-03-2099", "31-03-2099", http.StatusBadRequest, 0},
+		{"unpaid leave has no balance", models.LeaveTypeUnpaid, "01-03-2099", "31-03-2099", http.StatusCreated, 15},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupTestDB(t)
+			staff := createEmployees(t, "staff")[0]
+			// the first three weeks of March with two days off a week
+			var dates []string
+			for day := 1; day <= 21; day++ {
+				if day%7 != 6 && day%7 != 0 {
+					dates = append(dates, fmt.Sprintf("2099-03-%02d", day))
+				}
+			}
+			createSchedules(t, staff, models.ScheduleStatusPresent, dates...)
+
+			body := fmt.Sprintf(`{"leave_type_id":%d,"start_date":"%s","end_date":"%s"}`, leaveTypeID(t, tc.leaveType), tc.start, tc.end)
+			code, response := call(t, CreateLeaveRequest, staff, http.MethodPost, "/leave-requests/", body)
+			if code != tc.wantStatus {
+				t.Fatalf("got %d, want %d (%v)", code, tc.wantStatus, response)
+			}
+			if tc.wantStatus != http.StatusCreated {
+				return
+			}
+			if days := response["leave_request"].(map[string]interface{})["days"]; days != tc.wantDays {
+				t.Errorf("days = %v, want %v", days, tc.wantDays)
+			}
+		})
+	}
+}
+
+func TestCancelledLeaveRestoresScheduleStatus(t *testing.T) {
+	setupTestDB(t)
+	ids := createEmployees(t, "staff", "manager")
+	staff, manager := ids[0], ids[1]
+	createSchedules(t, staff, models.ScheduleStatusPresent, "2099-03-02")
+	createSchedules(t, staff, "training", "2099-03-03")
+
+	body := fmt.Sprintf(`{"leave_type_id":%d,"start_date":"02-03-2099","end_date":"03-03-2099"}`, leaveTypeID(t, models.LeaveTypeAnnual))
+	code, response := call(t, CreateLeaveRequest, staff, http.MethodPost, "/leave-requests/", body)
+	if code != http.StatusCreated {
+		t.Fatalf("create: got %d (%v)", code, response)
+	}
+	id := response["leave_request"].(map[string]interface{})["id"]
+	path := fmt.Sprintf("/leave-requests/%v", id)
+
+	if code, response := call(t, ApproveLeaveRequest, manager, http.MethodPut, path, ""); code != http.StatusOK {
+		t.Fatalf("approve: got %d (%v)", code, response)
+	}
+	var statuses []string
+	models.DB.Model(&models.Schedule{}).Order("date_schedule").Pluck("status", &statuses)
+	if fmt.Sprint(statuses) != "[cuti cuti]" {
+		t.Errorf("statuses during leave = %v, want [cuti cuti]", statuses)
+	}
+
+	if code, response := call(t, CancelLeaveRequest, staff, http.MethodPut, path, ""); code != http.StatusOK {
+		t.Fatalf("cancel: got %d (%v)", code, response)
+	}
+	statuses = nil
+	models.DB.Model(&models.Schedule{}).Order("date_schedule").Pluck("status", &statuses)
+	if fmt.Sprint(statuses) != "[hadir training]" {
+		t.Errorf("statuses after cancelling = %v, want [hadir training]", statuses)
+	}
+	var balance models.LeaveBalance
+	models.DB.Where("employee_id = ?", staff).First(&balance)
+	if balance.Used != 0 {
+		t.Errorf("used = %d after cancelling, want 0", balance.Used)
+	}
+}
@@ -0,0 +1,121 @@
+package leave
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListLeaveTypes returns every leave type
+func ListLeaveTypes(c *gin.Context) {
+	var leaveTypes []models.LeaveType
+	if err := models.DB.Order("id").Find(&leaveTypes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve leave types: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		data = append(data, formatLeaveType(leaveType))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":       false,
+		"message":     "Leave types retrieved successfully",
+		"leave_types": data,
+	})
+}
+
+// CreateLeaveType adds a leave type
+func CreateLeaveType(c *gin.Context) {
+	var input LeaveTypeInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	leaveType := models.LeaveType{
+		Code:          strings.ToLower(strings.TrimSpace(input.Code)),
+		Name:          input.Name,
+		DefaultDays:   input.DefaultDays,
+		TracksBalance: input.TracksBalance,
+		Paid:          input.Paid,
+	}
+
+	var count int64
+	models.DB.Model(&models.LeaveType{}).Where("code = ?", leaveType.Code).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "A leave type with this code already exists",
+		})
+		return
+	}
+
+	if err := models.DB.Create(&leaveType).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create leave type: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":      false,
+		"message":    "Leave type created successfully",
+		"leave_type": formatLeaveType(leaveType),
+	})
+}
+
+// UpdateLeaveType changes a leave type, balances already created keep their entitlement
+func UpdateLeaveType(c *gin.Context) {
+	var leaveType models.LeaveType
+	if err := models.DB.First(&leaveType, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Leave type not found",
+		})
+		return
+	}
+
+	var input LeaveTypeInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	code := strings.ToLower(strings.TrimSpace(input.Code))
+	var count int64
+	models.DB.Model(&models.LeaveType{}).Where("code = ? AND id <> ?", code, leaveType.ID).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "A leave type with this code already exists",
+		})
+		return
+	}
+
+	err := models.DB.Model(&leaveType).Updates(map[string]interface{}{
+		"code":           code,
+		"name":           input.Name,
+		"default_days":   input.DefaultDays,
+		"tracks_balance": input.TracksBalance,
+		"paid":           input.Paid,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update leave type: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":      false,
+		"message":    "Leave type updated successfully",
+		"leave_type": formatLeaveType(leaveType),
+	})
+}
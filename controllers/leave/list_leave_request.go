@@ -0,0 +1,57 @@
+package leave
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListMyLeaveRequests returns the leave requests of the logged-in employee, filter with ?status=
+func ListMyLeaveRequests(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	query := models.DB.Preload("Employee").Preload("LeaveType").Where("employee_id = ?", employee.Id)
+	respondLeaveRequests(c, query)
+}
+
+// ListDepartmentLeaveRequests returns the leave requests of the manager's department, filter with ?status=
+func ListDepartmentLeaveRequests(c *gin.Context) {
+	manager, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	query := models.DB.Preload("Employee").Preload("LeaveType").Where("department_id = ?", manager.Position.DepartmentId)
+	respondLeaveRequests(c, query)
+}
+
+func respondLeaveRequests(c *gin.Context, query *gorm.DB) {
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.LeaveRequest
+	if err := query.Order("start_date DESC, id DESC").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve leave requests: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(requests))
+	for _, request := range requests {
+		data = append(data, formatLeaveRequest(request))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":          false,
+		"message":        "Leave requests retrieved successfully",
+		"leave_requests": data,
+	})
+}
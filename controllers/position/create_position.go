@@ -36,8 +36,34 @@ func StorePosition(c *gin.Context) {
 		return
 	}
 
+	// positions without an explicit role get the regular employee role
+	var role models.Role
+	if input.RoleId != nil {
+		if err := models.DB.Preload("Permissions").Where("id = ?", *input.RoleId).First(&role).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Role not found!",
+			})
+			return
+		}
+		if !canGrantRole(c, role) {
+			return
+		}
+	} else {
+		defaultRole, err := models.FindRoleByName(models.RoleEmployee)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Default role not found",
+			})
+			return
+		}
+		role = defaultRole
+	}
+
 	position := models.Position{
 		DepartmentId: input.DepartmentId,
+		RoleId:       &role.Id,
 		PositionName: input.PositionName,
 		IsCompleted:  input.IsCompleted,
 	}
@@ -50,8 +76,10 @@ func StorePosition(c *gin.Context) {
 			"id":              position.Id,
 			"department_id":   position.DepartmentId,
 			"department_name": department.DepartmentName,
+			"role_id":         position.RoleId,
+			"role_name":       role.Name,
 			"position_name":   position.PositionName,
 			"is_completed":    position.IsCompleted,
 		},
 	})
-}
\ No newline at end of file
+}
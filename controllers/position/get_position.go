@@ -10,7 +10,7 @@ import (
 func FindPositions(c *gin.Context) {
 	var positions []models.Position
 
-	result := models.DB.Preload("Department").Find(&positions)
+	result := models.DB.Preload("Department").Preload("Role").Find(&positions)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
@@ -23,6 +23,8 @@ func FindPositions(c *gin.Context) {
 		Id             int    `json:"id"`
 		DepartmentId   int    `json:"department_id"`
 		DepartmentName string `json:"department_name"`
+		RoleId         *int   `json:"role_id"`
+		RoleName       string `json:"role_name"`
 		PositionName   string `json:"position_name"`
 		IsCompleted    bool   `json:"is_completed"`
 	}
@@ -33,6 +35,8 @@ func FindPositions(c *gin.Context) {
 			Id:             position.Id,
 			DepartmentId:   position.DepartmentId,
 			DepartmentName: position.Department.DepartmentName,
+			RoleId:         position.RoleId,
+			RoleName:       position.Role.Name,
 			PositionName:   position.PositionName,
 			IsCompleted:    position.IsCompleted,
 		}
@@ -44,4 +48,4 @@ func FindPositions(c *gin.Context) {
 		"message": "List Data Positions",
 		"data":    responsePositions,
 	})
-}
\ No newline at end of file
+}
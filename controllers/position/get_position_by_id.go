@@ -9,7 +9,7 @@ import (
 
 func FindPositionById(c *gin.Context) {
 	var position models.Position
-	if err := models.DB.Preload("Department").Where("id = ?", c.Param("id")).First(&position).Error; err != nil {
+	if err := models.DB.Preload("Department").Preload("Role").Where("id = ?", c.Param("id")).First(&position).Error; err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
 			"message": "Record not found!",
@@ -24,8 +24,10 @@ func FindPositionById(c *gin.Context) {
 			"id":              position.Id,
 			"department_id":   position.DepartmentId,
 			"department_name": position.Department.DepartmentName,
+			"role_id":         position.RoleId,
+			"role_name":       position.Role.Name,
 			"position_name":   position.PositionName,
 			"is_completed":    position.IsCompleted,
 		},
 	})
-}
\ No newline at end of file
+}
@@ -1,7 +1,36 @@
 package position
 
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
 type ValidatePositionInput struct {
 	DepartmentId int    `json:"department_id" binding:"required"`
 	PositionName string `json:"position_name" binding:"required"`
+	RoleId       *int   `json:"role_id"`
 	IsCompleted  bool   `json:"is_completed"`
-}
\ No newline at end of file
+}
+
+// canGrantRole answers 403 and returns false when the current employee may not link the role to a position,
+// so master data editors cannot hand out more access than they have
+func canGrantRole(c *gin.Context, role models.Role) bool {
+	var employee models.Employee
+	if err := models.DB.Preload("Position.Role.Permissions").First(&employee, c.GetInt("employeeId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return false
+	}
+	if !employee.Position.Role.CanGrant(role) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot assign the role " + role.Name + ", it grants permissions you do not have",
+		})
+		return false
+	}
+	return true
+}
@@ -45,10 +45,31 @@ func UpdatePosition(c *gin.Context) {
 		return
 	}
 
+	if input.RoleId != nil && (position.RoleId == nil || *position.RoleId != *input.RoleId) {
+		var role models.Role
+		if err := models.DB.Preload("Permissions").Where("id = ?", *input.RoleId).First(&role).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Role not found!",
+			})
+			return
+		}
+		if !canGrantRole(c, role) {
+			return
+		}
+		// taking a role away needs the same access as handing it out
+		if position.RoleId != nil {
+			var current models.Role
+			if err := models.DB.Preload("Permissions").First(&current, *position.RoleId).Error; err == nil && !canGrantRole(c, current) {
+				return
+			}
+		}
+	}
+
 	models.DB.Model(&position).Updates(input)
 
-	// Fetch updated position with department
-	models.DB.Preload("Department").Where("id = ?", position.Id).First(&position)
+	// Fetch updated position with department and role
+	models.DB.Preload("Department").Preload("Role").Where("id = ?", position.Id).First(&position)
 
 	c.JSON(200, gin.H{
 		"error":   false,
@@ -57,8 +78,10 @@ func UpdatePosition(c *gin.Context) {
 			"id":              position.Id,
 			"department_id":   position.DepartmentId,
 			"department_name": position.Department.DepartmentName,
+			"role_id":         position.RoleId,
+			"role_name":       position.Role.Name,
 			"position_name":   position.PositionName,
 			"is_completed":    position.IsCompleted,
 		},
 	})
-}
\ No newline at end of file
+}
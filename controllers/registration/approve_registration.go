@@ -0,0 +1,101 @@
+package registration
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ApproveRegistration activates a pending (or previously rejected) account, optionally changing its position
+func ApproveRegistration(c *gin.Context) {
+	reviewerId, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	var employee models.Employee
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&employee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Registration not found",
+		})
+		return
+	}
+
+	if employee.Status != models.EmployeeStatusPending && employee.Status != models.EmployeeStatusRejected {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only pending or rejected registrations can be approved",
+		})
+		return
+	}
+
+	// body is optional
+	var input ApproveRegistrationInput
+	if err := c.ShouldBindJSON(&input); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":      models.EmployeeStatusActive,
+		"reviewed_by": reviewerId,
+		"reviewed_at": time.Now(),
+		"review_note": input.Note,
+	}
+
+	// the account gets the role of its position, which must not grant more than the reviewer holds
+	positionId := employee.PositionId
+	if input.PositionId != nil {
+		positionId = *input.PositionId
+	}
+	var position models.Position
+	if err := models.DB.Preload("Role.Permissions").Where("id = ?", positionId).First(&position).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Position not found!",
+		})
+		return
+	}
+	var reviewer models.Employee
+	if err := models.DB.Preload("Position.Role.Permissions").First(&reviewer, reviewerId).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+	if !reviewer.Position.Role.CanGrant(position.Role) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot approve this account, the position role grants permissions you do not have",
+		})
+		return
+	}
+	updates["position_id"] = position.Id
+
+	if err := models.DB.Model(&employee).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to approve registration: " + err.Error(),
+		})
+		return
+	}
+
+	models.DB.Preload("Position.Department").First(&employee, employee.Id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":        false,
+		"message":      "Registration approved successfully",
+		"registration": formatRegistration(employee),
+	})
+}
@@ -0,0 +1,11 @@
+package registration
+
+type ApproveRegistrationInput struct {
+	// PositionId optionally replaces the position chosen during registration
+	PositionId *int   `json:"position_id"`
+	Note       string `json:"note"`
+}
+
+type RejectRegistrationInput struct {
+	Reason string `json:"reason" binding:"required"`
+}
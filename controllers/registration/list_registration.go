@@ -0,0 +1,58 @@
+package registration
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// FindRegistrations lists self registered accounts, pending ones by default (?status=rejected for rejected)
+func FindRegistrations(c *gin.Context) {
+	status := c.DefaultQuery("status", models.EmployeeStatusPending)
+	if status != models.EmployeeStatusPending && status != models.EmployeeStatusRejected {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Status must be pending or rejected",
+		})
+		return
+	}
+
+	var employees []models.Employee
+	if err := models.DB.Preload("Position.Department").Where("status = ?", status).Order("id").Find(&employees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve registrations: " + err.Error(),
+		})
+		return
+	}
+
+	registrations := make([]gin.H, 0, len(employees))
+	for _, employee := range employees {
+		registrations = append(registrations, formatRegistration(employee))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":         false,
+		"message":       "Registrations retrieved successfully",
+		"registrations": registrations,
+	})
+}
+
+func formatRegistration(employee models.Employee) gin.H {
+	return gin.H{
+		"id":    employee.Id,
+		"name":  employee.Name,
+		"email": employee.Email,
+		"phone": employee.Phone,
+		"position": gin.H{
+			"id":         employee.Position.Id,
+			"name":       employee.Position.PositionName,
+			"department": employee.Position.Department.DepartmentName,
+		},
+		"status":      employee.Status,
+		"reviewed_by": employee.ReviewedBy,
+		"reviewed_at": employee.ReviewedAt,
+		"review_note": employee.ReviewNote,
+	}
+}
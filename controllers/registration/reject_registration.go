@@ -0,0 +1,83 @@
+package registration
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// RejectRegistration marks a pending account as rejected, the employee can no longer log in
+func RejectRegistration(c *gin.Context) {
+	reviewerId, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	var employee models.Employee
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&employee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Registration not found",
+		})
+		return
+	}
+
+	if employee.Status != models.EmployeeStatusPending {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only pending registrations can be rejected",
+		})
+		return
+	}
+
+	var input RejectRegistrationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	if err := models.DB.Model(&employee).Updates(map[string]interface{}{
+		"status":      models.EmployeeStatusRejected,
+		"reviewed_by": reviewerId,
+		"reviewed_at": time.Now(),
+		"review_note": input.Reason,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to reject registration: " + err.Error(),
+		})
+		return
+	}
+
+	models.DB.Preload("Position.Department").First(&employee, employee.Id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":        false,
+		"message":      "Registration rejected successfully",
+		"registration": formatRegistration(employee),
+	})
+}
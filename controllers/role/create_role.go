@@ -0,0 +1,94 @@
+package role
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func StoreRole(c *gin.Context) {
+	var input ValidateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	var existingRole models.Role
+	if err := models.DB.Where("name = ?", input.Name).First(&existingRole).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Role '" + input.Name + "' already exists",
+		})
+		return
+	}
+
+	permissions, err := findPermissionsByName(input.Permissions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	role := models.Role{
+		Name:        input.Name,
+		Description: input.Description,
+		Permissions: permissions,
+	}
+	if err := models.DB.Create(&role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create role",
+		})
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"error":   false,
+		"message": "Role Created Successfully",
+		"data":    formatRole(role),
+	})
+}
+
+// findPermissionsByName loads the permissions and fails when one of the names does not exist
+func findPermissionsByName(names []string) ([]models.Permission, error) {
+	permissions := []models.Permission{}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := models.DB.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, errors.New("Permission '" + name + "' not found")
+		}
+	}
+	return permissions, nil
+}
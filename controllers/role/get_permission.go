@@ -0,0 +1,25 @@
+package role
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+func FindPermissions(c *gin.Context) {
+	var permissions []models.Permission
+	if err := models.DB.Find(&permissions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"error":   false,
+		"message": "List Data Permissions",
+		"data":    permissions,
+	})
+}
@@ -0,0 +1,42 @@
+package role
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+func FindRoles(c *gin.Context) {
+	var roles []models.Role
+	if err := models.DB.Preload("Permissions").Find(&roles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"error":   false,
+		"message": "List Data Roles",
+		"data":    formatRoles(roles),
+	})
+}
+
+func formatRoles(roles []models.Role) []gin.H {
+	data := make([]gin.H, 0, len(roles))
+	for _, role := range roles {
+		data = append(data, formatRole(role))
+	}
+	return data
+}
+
+func formatRole(role models.Role) gin.H {
+	return gin.H{
+		"id":          role.Id,
+		"name":        role.Name,
+		"description": role.Description,
+		"permissions": role.PermissionNames(),
+	}
+}
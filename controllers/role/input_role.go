@@ -0,0 +1,7 @@
+package role
+
+type ValidateRoleInput struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,96 @@
+package role
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// UpdateRole changes the name, description and the full permission list of a role
+func UpdateRole(c *gin.Context) {
+	var role models.Role
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&role).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Record not found!",
+		})
+		return
+	}
+
+	var input ValidateRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	// built-in roles are referenced by name in code, keep their names stable
+	if input.Name != role.Name && isBuiltInRole(role.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Built-in role '" + role.Name + "' cannot be renamed",
+		})
+		return
+	}
+
+	permissions, err := findPermissionsByName(input.Permissions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&role).Updates(map[string]interface{}{
+			"name":        input.Name,
+			"description": input.Description,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&role).Association("Permissions").Replace(permissions)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update role: " + err.Error(),
+		})
+		return
+	}
+
+	models.DB.Preload("Permissions").First(&role, role.Id)
+
+	c.JSON(200, gin.H{
+		"error":   false,
+		"message": "Role Updated Successfully",
+		"data":    formatRole(role),
+	})
+}
+
+func isBuiltInRole(name string) bool {
+	switch name {
+	case models.RoleEmployee, models.RoleSupervisor, models.RoleDepartmentManager, models.RoleHRAdmin, models.RoleSuperAdmin:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ApplyRosterTemplate generates the schedules of the seven days starting at week_start from a template.
+// Position slots apply to every employee of the position, an employee slot on the same day takes precedence.
+func ApplyRosterTemplate(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findDepartmentTemplate(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	var input ApplyRosterTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	weekStart, err := time.Parse("02-01-2006", input.WeekStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid week_start format. Use DD-MM-YYYY",
+		})
+		return
+	}
+
+	if err := input.LaborOverride.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	status := input.Status
+	if status == "" {
+		status = "hadir"
+	}
+
+	// employees of the positions used by position slots, still in the department
+	positionIDs := make([]int, 0)
+	for _, slot := range template.Slots {
+		if slot.PositionID != nil {
+			positionIDs = append(positionIDs, *slot.PositionID)
+		}
+	}
+	employeesByPosition := make(map[int][]models.Employee)
+	if len(positionIDs) > 0 {
+		var employees []models.Employee
+		if err := models.DB.Joins("JOIN positions ON positions.id = employees.position_id").
+			Where("employees.position_id IN ? AND positions.department_id = ?", positionIDs, template.DepartmentID).
+			Scopes(models.ActiveOn(weekStart.Format("2006-01-02"))).
+			Order("employees.name").
+			Find(&employees).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to load employees: " + err.Error(),
+			})
+			return
+		}
+		for _, employee := range employees {
+			employeesByPosition[employee.PositionId] = append(employeesByPosition[employee.PositionId], employee)
+		}
+	}
+
+	var planned []plannedSchedule
+	for day := 0; day < 7; day++ {
+		date := weekStart.AddDate(0, 0, day)
+
+		// employee slots first so they win over position slots for the same employee
+		assigned := make(map[int]bool)
+		for _, slot := range template.Slots {
+			if slot.Weekday != int(date.Weekday()) || slot.EmployeeID == nil {
+				continue
+			}
+			// skip employees that moved to another department since the template was saved
+			var employee models.Employee
+			if err := models.DB.Preload("Position").First(&employee, *slot.EmployeeID).Error; err != nil ||
+				employee.Position.DepartmentId != template.DepartmentID {
+				continue
+			}
+			assigned[employee.Id] = true
+			planned = append(planned, plannedSchedule{Employee: employee, ShiftID: slot.ShiftID, Date: date})
+		}
+		for _, slot := range template.Slots {
+			if slot.Weekday != int(date.Weekday()) || slot.PositionID == nil {
+				continue
+			}
+			for _, employee := range employeesByPosition[*slot.PositionID] {
+				if !assigned[employee.Id] {
+					planned = append(planned, plannedSchedule{Employee: employee, ShiftID: slot.ShiftID, Date: date})
+				}
+			}
+		}
+	}
+
+	batch, err := planScheduleBatch(planned, template.DepartmentID, uint(manager.Id), status, input.LaborOverride)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load existing schedules: " + err.Error(),
+		})
+		return
+	}
+
+	saveScheduleBatch(c, batch, input.OnConflict, input.DryRun)
+}
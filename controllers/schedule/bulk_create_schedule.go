@@ -0,0 +1,272 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxBulkScheduleDays limits the date range of a single bulk request
+const maxBulkScheduleDays = 93
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// BulkScheduleAssignment is the rotation of one employee, either Weekly or Cycle must be set
+type BulkScheduleAssignment struct {
+	EmployeeID uint `json:"employee_id" binding:"required"`
+	// Weekly maps a weekday (monday ... sunday) to a shift id, missing days are off
+	Weekly map[string]uint `json:"weekly"`
+	// Cycle is repeated day after day from the start date, 0 is a day off (e.g. [1, 2, 3, 0])
+	Cycle []uint `json:"cycle"`
+	// CycleOffset is the index in Cycle used for the start date, to stagger employees on the same cycle
+	CycleOffset int `json:"cycle_offset" binding:"min=0"`
+}
+
+type BulkCreateScheduleRequest struct {
+	StartDate   string                   `json:"start_date" binding:"required"`
+	EndDate     string                   `json:"end_date" binding:"required"`
+	Status      string                   `json:"status"`
+	Assignments []BulkScheduleAssignment `json:"assignments" binding:"required,min=1,dive"`
+	// OnConflict is "skip" (default) to create the other rows, or "abort" to create nothing when a row conflicts
+	OnConflict string `json:"on_conflict" binding:"omitempty,oneof=skip abort"`
+	// DryRun only returns the report
+	DryRun bool `json:"dry_run"`
+	LaborOverride
+}
+
+// shiftOn returns the shift of the assignment on the given day, 0 for a day off
+func (a BulkScheduleAssignment) shiftOn(date time.Time, dayIndex int) uint {
+	if len(a.Cycle) > 0 {
+		return a.Cycle[(dayIndex+a.CycleOffset)%len(a.Cycle)]
+	}
+	for day, shiftID := range a.Weekly {
+		if strings.EqualFold(day, date.Weekday().String()) {
+			return shiftID
+		}
+	}
+	return 0
+}
+
+// validate checks the pattern of the assignment and returns the shift ids it uses
+func (a BulkScheduleAssignment) validate() ([]uint, error) {
+	if (len(a.Weekly) > 0) == (len(a.Cycle) > 0) {
+		return nil, errors.New("set either weekly or cycle")
+	}
+
+	var shiftIDs []uint
+	for day, shiftID := range a.Weekly {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return nil, fmt.Errorf("unknown weekday %q", day)
+		}
+		if shiftID != 0 {
+			shiftIDs = append(shiftIDs, shiftID)
+		}
+	}
+	for _, shiftID := range a.Cycle {
+		if shiftID != 0 {
+			shiftIDs = append(shiftIDs, shiftID)
+		}
+	}
+	if len(shiftIDs) == 0 {
+		return nil, errors.New("the pattern has no working days")
+	}
+	return shiftIDs, nil
+}
+
+// BulkCreateSchedules generates the schedules of several employees over a date range from a weekly
+// template or a repeating shift cycle. All rows are created in one transaction and a per-row report is returned.
+func BulkCreateSchedules(c *gin.Context) {
+	employeeID, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	var creator models.Employee
+	if err := models.DB.Preload("Position").First(&creator, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return
+	}
+
+	var request BulkCreateScheduleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	startDate, err := time.Parse("02-01-2006", request.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid start_date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	endDate, err := time.Parse("02-01-2006", request.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid end_date format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	if days < 1 || days > maxBulkScheduleDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": fmt.Sprintf("end_date must be on or after start_date and the range at most %d days", maxBulkScheduleDays),
+		})
+		return
+	}
+
+	if err := request.LaborOverride.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	status := request.Status
+	if status == "" {
+		status = "hadir"
+	}
+
+	// validate every assignment before generating anything
+	var assignmentErrors []gin.H
+	employeeIDs := make([]uint, 0, len(request.Assignments))
+	seenEmployees := make(map[uint]bool)
+	shiftSet := make(map[uint]bool)
+	for i, assignment := range request.Assignments {
+		if seenEmployees[assignment.EmployeeID] {
+			assignmentErrors = append(assignmentErrors, gin.H{"index": i, "message": "employee appears more than once"})
+			continue
+		}
+		seenEmployees[assignment.EmployeeID] = true
+		employeeIDs = append(employeeIDs, assignment.EmployeeID)
+
+		shiftIDs, err := assignment.validate()
+		if err != nil {
+			assignmentErrors = append(assignmentErrors, gin.H{"index": i, "message": err.Error()})
+			continue
+		}
+		for _, shiftID := range shiftIDs {
+			shiftSet[shiftID] = true
+		}
+	}
+
+	var employees []models.Employee
+	if err := models.DB.Preload("Position").Where("id IN ?", employeeIDs).Find(&employees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load employees: " + err.Error(),
+		})
+		return
+	}
+	employeesByID := make(map[uint]models.Employee, len(employees))
+	for _, employee := range employees {
+		employeesByID[uint(employee.Id)] = employee
+	}
+
+	shiftIDs := make([]uint, 0, len(shiftSet))
+	for shiftID := range shiftSet {
+		shiftIDs = append(shiftIDs, shiftID)
+	}
+	var shifts []models.Shift
+	if err := models.DB.Where("id IN ?", shiftIDs).Find(&shifts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load shifts: " + err.Error(),
+		})
+		return
+	}
+	knownShifts := make(map[uint]bool, len(shifts))
+	for _, shift := range shifts {
+		knownShifts[shift.ID] = true
+	}
+
+	for i, assignment := range request.Assignments {
+		employee, ok := employeesByID[assignment.EmployeeID]
+		if !ok {
+			assignmentErrors = append(assignmentErrors, gin.H{"index": i, "message": "Employee not found"})
+			continue
+		}
+		if employee.Position.DepartmentId != creator.Position.DepartmentId {
+			assignmentErrors = append(assignmentErrors, gin.H{"index": i, "message": "You can only create schedules for employees in your department"})
+		}
+	}
+	for _, shiftID := range shiftIDs {
+		if !knownShifts[shiftID] {
+			assignmentErrors = append(assignmentErrors, gin.H{"message": fmt.Sprintf("Shift %d not found", shiftID)})
+		}
+	}
+
+	if len(assignmentErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Validation failed",
+			"errors":  assignmentErrors,
+		})
+		return
+	}
+
+	var planned []plannedSchedule
+	for _, assignment := range request.Assignments {
+		employee := employeesByID[assignment.EmployeeID]
+
+		for dayIndex := 0; dayIndex < days; dayIndex++ {
+			date := startDate.AddDate(0, 0, dayIndex)
+			if shiftID := assignment.shiftOn(date, dayIndex); shiftID != 0 {
+				planned = append(planned, plannedSchedule{Employee: employee, ShiftID: shiftID, Date: date})
+			}
+		}
+	}
+
+	batch, err := planScheduleBatch(planned, creator.Position.DepartmentId, uint(creator.Id), status, request.LaborOverride)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load existing schedules: " + err.Error(),
+		})
+		return
+	}
+
+	saveScheduleBatch(c, batch, request.OnConflict, request.DryRun)
+}
@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBulkScheduleAssignmentShiftOn(t *testing.T) {
+	// Monday 2 March 2099 is the start date, day index 0
+	start := time.Date(2099, 3, 2, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		assignment BulkScheduleAssignment
+		want       []uint // Monday to Sunday
+	}{
+		{"weekly", BulkScheduleAssignment{Weekly: map[string]uint{"monday": 1, "Wednesday": 2, "sunday": 1}},
+			[]uint{1, 0, 2, 0, 0, 0, 1}},
+		{"cycle", BulkScheduleAssignment{Cycle: []uint{1, 2, 0}}, []uint{1, 2, 0, 1, 2, 0, 1}},
+		{"cycle with offset", BulkScheduleAssignment{Cycle: []uint{1, 2, 0}, CycleOffset: 2}, []uint{0, 1, 2, 0, 1, 2, 0}},
+		{"offset past the cycle length", BulkScheduleAssignment{Cycle: []uint{1, 0}, CycleOffset: 3}, []uint{0, 1, 0, 1, 0, 1, 0}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := make([]uint, 7)
+			for i := range got {
+				got[i] = tc.assignment.shiftOn(start.AddDate(0, 0, i), i)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("shifts = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBulkScheduleAssignmentValidate(t *testing.T) {
+	cases := []struct {
+		name       string
+		assignment BulkScheduleAssignment
+		wantErr    bool
+	}{
+		{"weekly", BulkScheduleAssignment{Weekly: map[string]uint{"monday": 1}}, false},
+		{"cycle", BulkScheduleAssignment{Cycle: []uint{1, 0}}, false},
+		{"both patterns", BulkScheduleAssignment{Weekly: map[string]uint{"monday": 1}, Cycle: []uint{1}}, true},
+		{"no pattern", BulkScheduleAssignment{}, true},
+		{"unknown weekday", BulkScheduleAssignment{Weekly: map[string]uint{"funday": 1}}, true},
+		{"only days off", BulkScheduleAssignment{Cycle: []uint{0, 0}}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.assignment.validate(); (err != nil) != tc.wantErr {
+				t.Errorf("err = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
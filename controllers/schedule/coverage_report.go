@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// CoverageReport compares the schedules of the caller's department with its staffing rules between
+// ?start_date= and ?end_date= (DD-MM-YYYY, default the next seven days). ?shift_id= limits the report
+// to one shift and ?only_gaps=true leaves out the correctly staffed slots.
+func CoverageReport(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid start_date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		startDate = parsed
+	}
+	endDate := startDate.AddDate(0, 0, 6)
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid end_date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		endDate = parsed
+	}
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	if days < 1 || days > maxBulkScheduleDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": fmt.Sprintf("end_date must be on or after start_date and the range at most %d days", maxBulkScheduleDays),
+		})
+		return
+	}
+
+	var shiftID uint
+	if value := c.Query("shift_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid shift_id",
+			})
+			return
+		}
+		shiftID = uint(parsed)
+	}
+
+	slots, err := models.ComputeCoverage(models.DB, manager.Position.DepartmentId, startDate, endDate, shiftID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to compute coverage: " + err.Error(),
+		})
+		return
+	}
+
+	summary := map[string]int{models.CoverageOK: 0, models.CoverageUnderstaffed: 0, models.CoverageOverstaffed: 0}
+	onlyGaps := c.Query("only_gaps") == "true"
+	report := make([]models.CoverageSlot, 0, len(slots))
+	for _, slot := range slots {
+		summary[slot.Status]++
+		if onlyGaps && slot.Status == models.CoverageOK {
+			continue
+		}
+		report = append(report, slot)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":      false,
+		"message":    "Coverage report retrieved successfully",
+		"start_date": startDate.Format("02-01-2006"),
+		"end_date":   endDate.Format("02-01-2006"),
+		"summary":    summary,
+		"slots":      report,
+	})
+}
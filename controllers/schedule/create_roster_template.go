@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// CreateRosterTemplate saves a weekly layout of shift assignments for the caller's department
+func CreateRosterTemplate(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	var input RosterTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	slots, slotErrors := buildTemplateSlots(input.Slots, manager.Position.DepartmentId)
+	if len(slotErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Validation failed",
+			"errors":  slotErrors,
+		})
+		return
+	}
+
+	template := models.RosterTemplate{
+		Name:         input.Name,
+		DepartmentID: manager.Position.DepartmentId,
+		CreatedBy:    uint(manager.Id),
+		Slots:        slots,
+	}
+	if err := models.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create roster template: " + err.Error(),
+		})
+		return
+	}
+
+	template, _ = loadRosterTemplate(template.ID, manager.Position.DepartmentId)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":    false,
+		"message":  "Roster template created successfully",
+		"template": formatRosterTemplate(template),
+	})
+}
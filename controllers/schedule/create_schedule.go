@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
 type CreateScheduleRequest struct {
@@ -15,6 +17,7 @@ type CreateScheduleRequest struct {
 	ShiftID      uint   `json:"shift_id" binding:"required"`
 	DateSchedule string `json:"date_schedule" binding:"required"`
 	Status       string `json:"status" binding:"required"`
+	LaborOverride
 }
 
 func CreateSchedule(c *gin.Context) {
@@ -37,16 +40,6 @@ func CreateSchedule(c *gin.Context) {
 		return
 	}
 
-	// Check if employee is manager/supervisor
-	var isManager bool
-	if result := models.DB.Raw("SELECT position_name LIKE '%manager%' OR position_name LIKE '%supervisor%' OR position_name LIKE '%executive%' FROM positions WHERE id = ?", creator.PositionId).Scan(&isManager); result.Error != nil || !isManager {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   true,
-			"message": "You don't have permission to create schedules",
-		})
-		return
-	}
-
 	// Parse request body
 	var request CreateScheduleRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -96,6 +89,31 @@ func CreateSchedule(c *gin.Context) {
 		return
 	}
 
+	// Deactivated or former employees cannot be scheduled
+	if !employee.IsActiveOn(mysqlFormattedDate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Employee is not active on this date",
+		})
+		return
+	}
+
+	onLeave, err := models.IsOnLeave(models.DB, uint(employee.Id), mysqlFormattedDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check leave: " + err.Error(),
+		})
+		return
+	}
+	if onLeave {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Employee is on approved leave on this date",
+		})
+		return
+	}
+
 	// Verify shift exists
 	var shift models.Shift
 	if err := models.DB.First(&shift, request.ShiftID).Error; err != nil {
@@ -106,16 +124,43 @@ func CreateSchedule(c *gin.Context) {
 		return
 	}
 
-	// Check if a schedule already exists for this employee on this date
-	var existingSchedule models.Schedule
-	result := models.DB.Where("employee_id = ? AND date(date_schedule) = ?", request.EmployeeID, dateSchedule.Format("2006-01-02")).First(&existingSchedule)
-	if result.RowsAffected > 0 {
+	// Several schedules on one date are allowed (split shifts) as long as their shifts do not overlap
+	overlapping, err := models.FindOverlappingSchedule(models.DB, request.EmployeeID, dateSchedule, shift)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check existing schedules: " + err.Error(),
+		})
+		return
+	}
+	if overlapping != nil {
 		c.JSON(http.StatusConflict, gin.H{
+			"error":       true,
+			"message":     "The shift overlaps another schedule of this employee",
+			"schedule_id": overlapping.ID,
+		})
+		return
+	}
+
+	if err := request.LaborOverride.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   true,
-			"message": "A schedule already exists for this employee on this date",
+			"message": err.Error(),
 		})
 		return
 	}
+	violations, err := models.CheckLaborRules(models.DB, config.App.Labor, request.EmployeeID, dateSchedule, shift)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check labor rules: " + err.Error(),
+		})
+		return
+	}
+	if len(violations) > 0 && !request.OverrideLaborRules {
+		respondLaborViolations(c, violations)
+		return
+	}
 
 	schedule := models.Schedule{
 		EmployeeID:   request.EmployeeID,
@@ -125,7 +170,35 @@ func CreateSchedule(c *gin.Context) {
 		Status:       request.Status,
 	}
 
-	if err := models.DB.Create(&schedule).Error; err != nil {
+	// the schedule stays a draft until its week is published, changes to a published week are recorded
+	published, err := models.IsWeekPublished(models.DB, employee.Position.DepartmentId, mysqlFormattedDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check publication: " + err.Error(),
+		})
+		return
+	}
+	schedule.Draft = !published
+
+	coverageBefore := models.CoverageOn(models.DB, employee.Position.DepartmentId, mysqlFormattedDate)
+
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&schedule).Error; err != nil {
+			return err
+		}
+		if published {
+			change := models.NewScheduleChange(schedule, employee.Position.DepartmentId, models.ScheduleChangeCreated, uint(creator.Id))
+			if err := models.RecordScheduleChange(tx, change); err != nil {
+				return err
+			}
+		}
+		if len(violations) > 0 {
+			return recordLaborOverride(tx, c, schedule, violations, request.OverrideReason)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to create schedule: " + err.Error(),
@@ -148,6 +221,12 @@ func CreateSchedule(c *gin.Context) {
 		dateForResponse = t.Format("02-01-2006")
 	}
 
+	holidayDate := completeSchedule.DateSchedule
+	if len(holidayDate) > 10 {
+		holidayDate = holidayDate[:10]
+	}
+	holiday := holidaysBetween(holidayDate, holidayDate).Name(holidayDate)
+
 	// Format response
 	scheduleResponse := gin.H{
 		"id": completeSchedule.ID,
@@ -164,6 +243,9 @@ func CreateSchedule(c *gin.Context) {
 		},
 		"date_schedule": dateForResponse,
 		"status":        completeSchedule.Status,
+		"draft":         completeSchedule.Draft,
+		"is_holiday":    holiday != "",
+		"holiday_name":  holiday,
 		"created_by": gin.H{
 			"id":   completeSchedule.Creator.Id,
 			"name": completeSchedule.Creator.Name,
@@ -173,8 +255,10 @@ func CreateSchedule(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"error":    false,
-		"message":  "Schedule created successfully",
-		"schedule": scheduleResponse,
+		"error":             false,
+		"message":           "Schedule created successfully",
+		"schedule":          scheduleResponse,
+		"coverage_warnings": models.CoverageWarnings(coverageBefore, models.CoverageOn(models.DB, employee.Position.DepartmentId, mysqlFormattedDate)),
+		"labor_violations":  violations,
 	})
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// DeleteRosterTemplate deletes a template, schedules generated from it are kept
+func DeleteRosterTemplate(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findDepartmentTemplate(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("roster_template_id = ?", template.ID).Delete(&models.RosterTemplateSlot{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&template).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete roster template: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Roster template deleted successfully",
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // DeleteSchedule handles DELETE /api/schedules/{id}
@@ -29,16 +30,6 @@ func DeleteSchedule(c *gin.Context) {
 		return
 	}
 
-	// Check if employee is manager/supervisor
-	var isManager bool
-	if result := models.DB.Raw("SELECT position_name LIKE '%manager%' OR position_name LIKE '%supervisor%' OR position_name LIKE '%executive%'  FROM positions WHERE id = ?", employee.PositionId).Scan(&isManager); result.Error != nil || !isManager {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   true,
-			"message": "You don't have permission to delete schedules",
-		})
-		return
-	}
-
 	// Get schedule ID from URL parameter
 	scheduleID := c.Param("id")
 	if scheduleID == "" {
@@ -78,8 +69,20 @@ func DeleteSchedule(c *gin.Context) {
 		return
 	}
 
-	// Delete the schedule
-	if err := models.DB.Delete(&schedule).Error; err != nil {
+	coverageBefore := models.CoverageOn(models.DB, schedule.Employee.Position.DepartmentId, schedule.DateSchedule)
+
+	// Delete the schedule, employees are told when it was published
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&schedule).Error; err != nil {
+			return err
+		}
+		if schedule.Draft {
+			return nil
+		}
+		change := models.NewScheduleChange(schedule, schedule.Employee.Position.DepartmentId, models.ScheduleChangeDeleted, uint(employee.Id))
+		return models.RecordScheduleChange(tx, change)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to delete schedule: " + err.Error(),
@@ -89,7 +92,8 @@ func DeleteSchedule(c *gin.Context) {
 
 	// Return success response
 	c.JSON(http.StatusOK, gin.H{
-		"error":   false,
-		"message": "Schedule deleted successfully",
+		"error":             false,
+		"message":           "Schedule deleted successfully",
+		"coverage_warnings": models.CoverageWarnings(coverageBefore, models.CoverageOn(models.DB, schedule.Employee.Position.DepartmentId, schedule.DateSchedule)),
 	})
 }
\ No newline at end of file
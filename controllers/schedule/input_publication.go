@@ -0,0 +1,6 @@
+package schedule
+
+type PublishSchedulesInput struct {
+	// WeekStart (DD-MM-YYYY) is any day of the week to publish, weeks run from Monday to Sunday
+	WeekStart string `json:"week_start" binding:"required"`
+}
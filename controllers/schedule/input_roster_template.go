@@ -0,0 +1,23 @@
+package schedule
+
+// RosterTemplateSlotInput assigns a shift on a weekday to either an employee or a position
+type RosterTemplateSlotInput struct {
+	Weekday    string `json:"weekday" binding:"required"`
+	EmployeeID *uint  `json:"employee_id"`
+	PositionID *int   `json:"position_id"`
+	ShiftID    uint   `json:"shift_id" binding:"required"`
+}
+
+type RosterTemplateInput struct {
+	Name  string                    `json:"name" binding:"required,max=100"`
+	Slots []RosterTemplateSlotInput `json:"slots" binding:"required,min=1,dive"`
+}
+
+type ApplyRosterTemplateInput struct {
+	// WeekStart (DD-MM-YYYY) is the first of the seven days the template is applied to
+	WeekStart  string `json:"week_start" binding:"required"`
+	Status     string `json:"status"`
+	OnConflict string `json:"on_conflict" binding:"omitempty,oneof=skip abort"`
+	DryRun     bool   `json:"dry_run"`
+	LaborOverride
+}
@@ -0,0 +1,11 @@
+package schedule
+
+type StaffingRuleInput struct {
+	// PositionID limits the rule to one position, omit it to count every position of the department
+	PositionID *int `json:"position_id"`
+	ShiftID    uint `json:"shift_id" binding:"required"`
+	// Weekday (monday ... sunday) limits the rule to one day of the week, omit it for every day
+	Weekday  string `json:"weekday"`
+	MinStaff int    `json:"min_staff" binding:"min=0"`
+	MaxStaff *int   `json:"max_staff" binding:"omitempty,min=0"`
+}
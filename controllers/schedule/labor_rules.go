@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LaborOverride lets a manager keep a schedule that breaks labor rules, the reason is written to the audit log
+type LaborOverride struct {
+	OverrideLaborRules bool   `json:"override_labor_rules"`
+	OverrideReason     string `json:"override_reason" binding:"max=255"`
+}
+
+func (o LaborOverride) validate() error {
+	if o.OverrideLaborRules && strings.TrimSpace(o.OverrideReason) == "" {
+		return errors.New("override_reason is required to override labor rules")
+	}
+	return nil
+}
+
+// normalizeScheduleDate cuts dates read back as timestamps to YYYY-MM-DD
+func normalizeScheduleDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// respondLaborViolations rejects a schedule that breaks labor rules
+func respondLaborViolations(c *gin.Context, violations []models.LaborViolation) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error":      true,
+		"message":    "The schedule breaks labor rules, set override_labor_rules with an override_reason to keep it",
+		"violations": violations,
+	})
+}
+
+// recordLaborOverride writes the overridden rules and the reason of the manager to the audit log
+func recordLaborOverride(tx *gorm.DB, c *gin.Context, schedule models.Schedule, violations []models.LaborViolation, reason string) error {
+	actorID := c.GetInt("employeeId")
+	employeeID := int(schedule.EmployeeID)
+
+	rules := make([]string, 0, len(violations))
+	seen := make(map[string]bool)
+	for _, violation := range violations {
+		if !seen[violation.Rule] {
+			seen[violation.Rule] = true
+			rules = append(rules, violation.Rule)
+		}
+	}
+	date, _ := time.Parse("2006-01-02", normalizeScheduleDate(schedule.DateSchedule))
+
+	return models.RecordAudit(tx, models.AuditLog{
+		Action:     models.AuditScheduleLaborOverride,
+		ActorID:    &actorID,
+		EmployeeID: &employeeID,
+		IPAddress:  c.ClientIP(),
+		Detail: fmt.Sprintf("schedule %d on %s breaks %s: %s",
+			schedule.ID, date.Format("02-01-2006"), strings.Join(rules, ", "), strings.TrimSpace(reason)),
+	})
+}
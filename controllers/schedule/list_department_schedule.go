@@ -29,16 +29,6 @@ func ListDepartmentSchedules(c *gin.Context) {
 		return
 	}
 
-	// Check if employee is manager/supervisor/executive
-	var isManager bool
-	if result := models.DB.Raw("SELECT position_name LIKE '%manager%' OR position_name LIKE '%supervisor%' OR position_name LIKE '%executive%' FROM positions WHERE id = ?", employee.PositionId).Scan(&isManager); result.Error != nil || !isManager {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   true,
-			"message": "You don't have permission to access this resource",
-		})
-		return
-	}
-
 	// Get department ID from employee's position or from query parameter
 	departmentID := c.Query("department_id")
 	var deptID uint
@@ -110,6 +100,7 @@ func ListDepartmentSchedules(c *gin.Context) {
 		ShiftClockOut    string    `json:"shift_clock_out"`
 		DateSchedule     string    `json:"date_schedule"`
 		Status           string    `json:"status"`
+		Draft            bool      `json:"draft"`
 		CreatorID        uint      `json:"creator_id"`
 		CreatorName      string    `json:"creator_name"`
 		CreatedAt        time.Time `json:"created_at"`
@@ -124,7 +115,7 @@ func ListDepartmentSchedules(c *gin.Context) {
 		Joins("JOIN positions p ON e.position_id = p.id").
 		Joins("JOIN shifts sh ON s.shift_id = sh.id").
 		Joins("JOIN employees creator ON s.created_by = creator.id").
-		Where("p.department_id = ? AND DATE(s.date_schedule) = ?", deptID, formattedDate)
+		Where("p.department_id = ? AND s.date_schedule = ?", deptID, formattedDate)
 
 	// Add status filter if provided
 	if status != "" {
@@ -143,6 +134,7 @@ func ListDepartmentSchedules(c *gin.Context) {
 		sh.end_time as shift_clock_out,
 		s.date_schedule, 
 		s.status, 
+		s.draft, 
 		creator.id as creator_id, 
 		creator.name as creator_name,
 		s.created_at,
@@ -157,6 +149,8 @@ func ListDepartmentSchedules(c *gin.Context) {
 		return
 	}
 
+	holiday := holidaysBetween(formattedDate, formattedDate).Name(formattedDate)
+
 	// Convert the raw data to the expected response format
 	schedules := make([]gin.H, 0, len(schedulesData))
 	for _, s := range schedulesData {
@@ -181,6 +175,9 @@ func ListDepartmentSchedules(c *gin.Context) {
 			},
 			"date_schedule": dateStr,
 			"status":        s.Status,
+			"draft":         s.Draft,
+			"is_holiday":    holiday != "",
+			"holiday_name":  holiday,
 			"created_by": gin.H{
 				"id":   s.CreatorID,
 				"name": s.CreatorName,
@@ -195,7 +192,9 @@ func ListDepartmentSchedules(c *gin.Context) {
 		"error":   false,
 		"message": "Schedule data retrieved successfully",
 		"meta": gin.H{
-			"date": scheduleDate.Format("02-01-2006"),
+			"date":         scheduleDate.Format("02-01-2006"),
+			"is_holiday":   holiday != "",
+			"holiday_name": holiday,
 			"department": gin.H{
 				"id":   department.Id,
 				"name": department.DepartmentName,
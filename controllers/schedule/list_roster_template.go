@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListRosterTemplates returns the roster templates of the caller's department
+func ListRosterTemplates(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	var templates []models.RosterTemplate
+	if err := models.DB.
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("weekday, id") }).
+		Preload("Slots.Employee").Preload("Slots.Position").Preload("Slots.Shift").
+		Where("department_id = ?", manager.Position.DepartmentId).
+		Order("name").
+		Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve roster templates: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(templates))
+	for _, template := range templates {
+		data = append(data, formatRosterTemplate(template))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":     false,
+		"message":   "Roster templates retrieved successfully",
+		"templates": data,
+	})
+}
+
+// GetRosterTemplate returns one roster template of the caller's department
+func GetRosterTemplate(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findDepartmentTemplate(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Roster template retrieved successfully",
+		"template": formatRosterTemplate(template),
+	})
+}
@@ -42,8 +42,10 @@ func ListSchedules(c *gin.Context) {
 	// Query to get employee's schedules for current and next month
 	query := models.DB.Table("schedules s").
 		Joins("JOIN shifts sh ON s.shift_id = sh.id").
-		Where("s.employee_id = ? AND DATE(s.date_schedule) >= ? AND DATE(s.date_schedule) < ?", 
-			employeeID, currentMonth.Format("2006-01-02"), nextMonth.Format("2006-01-02"))
+		Where("s.employee_id = ? AND s.date_schedule >= ? AND s.date_schedule < ?", 
+			employeeID, currentMonth.Format("2006-01-02"), nextMonth.Format("2006-01-02")).
+		// draft schedules stay hidden until their week is published
+		Where("s.draft = ?", false)
 
 	// Execute the query and retrieve schedules
 	result := query.Select(`
@@ -65,6 +67,8 @@ func ListSchedules(c *gin.Context) {
 		return
 	}
 
+	holidays := holidaysBetween(currentMonth.Format("2006-01-02"), nextMonth.AddDate(0, 0, -1).Format("2006-01-02"))
+
 	// Convert the raw data to the expected response format
 	schedules := make([]gin.H, 0, len(schedulesData))
 	for _, s := range schedulesData {
@@ -74,9 +78,12 @@ func ListSchedules(c *gin.Context) {
 			dateStr = t.Format("02-01-2006") // Format to DD-MM-YYYY
 		}
 		
+		holiday := holidays.Name(s.DateSchedule)
 		schedules = append(schedules, gin.H{
 			"id": s.ID,
 			"date_schedule": dateStr,
+			"is_holiday":    holiday != "",
+			"holiday_name":  holiday,
 			"shift": gin.H{
 				"id":         s.ShiftID,
 				"start_time": s.ShiftStart,
@@ -0,0 +1,87 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// publishedWeeks returns the published weeks of the department between from and to, keyed by their Monday (YYYY-MM-DD)
+func publishedWeeks(departmentID int, from, to time.Time) (map[string]bool, error) {
+	var publications []models.SchedulePublication
+	if err := models.DB.Where("department_id = ? AND week_start BETWEEN ? AND ?", departmentID,
+		models.WeekStart(from).Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&publications).Error; err != nil {
+		return nil, err
+	}
+	weeks := make(map[string]bool, len(publications))
+	for _, publication := range publications {
+		weeks[normalizeScheduleDate(publication.WeekStart)] = true
+	}
+	return weeks, nil
+}
+
+// departmentEmployeeIDs is a subquery of the employees whose position belongs to the department
+func departmentEmployeeIDs(db *gorm.DB, departmentID int) *gorm.DB {
+	return db.Model(&models.Employee{}).
+		Select("employees.id").
+		Joins("JOIN positions ON positions.id = employees.position_id").
+		Where("positions.department_id = ?", departmentID)
+}
+
+// formatScheduleDate turns a stored date into DD-MM-YYYY
+func formatScheduleDate(date string) string {
+	if t, err := time.Parse("2006-01-02", normalizeScheduleDate(date)); err == nil {
+		return t.Format("02-01-2006")
+	}
+	return date
+}
+
+func formatSchedulePublication(publication models.SchedulePublication) gin.H {
+	weekStart, _ := time.Parse("2006-01-02", normalizeScheduleDate(publication.WeekStart))
+	return gin.H{
+		"id":         publication.ID,
+		"week_start": weekStart.Format("02-01-2006"),
+		"week_end":   weekStart.AddDate(0, 0, 6).Format("02-01-2006"),
+		"published_by": gin.H{
+			"id":   publication.Publisher.Id,
+			"name": publication.Publisher.Name,
+		},
+		"published_at": publication.PublishedAt.Format(time.RFC3339),
+	}
+}
+
+func formatScheduleChange(change models.ScheduleChange) gin.H {
+	var previousDate *string
+	if change.PreviousDate != nil {
+		date := formatScheduleDate(*change.PreviousDate)
+		previousDate = &date
+	}
+	return gin.H{
+		"id":          change.ID,
+		"schedule_id": change.ScheduleID,
+		"action":      change.Action,
+		"employee": gin.H{
+			"id":   change.Employee.Id,
+			"name": change.Employee.Name,
+		},
+		"date_schedule": formatScheduleDate(change.DateSchedule),
+		"shift": gin.H{
+			"id":        change.Shift.ID,
+			"name":      change.Shift.Type,
+			"clock_in":  change.Shift.StartTime,
+			"clock_out": change.Shift.EndTime,
+		},
+		"status":               change.Status,
+		"previous_employee_id": change.PreviousEmployeeID,
+		"previous_date":        previousDate,
+		"previous_shift_id":    change.PreviousShiftID,
+		"changed_by": gin.H{
+			"id":   change.Changer.Id,
+			"name": change.Changer.Name,
+		},
+		"created_at": change.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,213 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// maxPublicationWeeks limits the weeks reported at once
+const maxPublicationWeeks = 14
+
+// PublishSchedules makes every draft schedule of the caller's department in a week visible to the employees.
+// Schedules created in the week afterwards are published right away and their changes are recorded.
+func PublishSchedules(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	var input PublishSchedulesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	date, err := time.Parse("02-01-2006", input.WeekStart)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Invalid week_start format. Use DD-MM-YYYY",
+		})
+		return
+	}
+	weekStart := models.WeekStart(date)
+	departmentID := manager.Position.DepartmentId
+
+	publication := models.SchedulePublication{
+		DepartmentID: departmentID,
+		WeekStart:    weekStart.Format("2006-01-02"),
+	}
+	var published int64
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(publication).
+			Attrs(models.SchedulePublication{PublishedBy: uint(manager.Id), PublishedAt: time.Now()}).
+			FirstOrCreate(&publication).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.Schedule{}).
+			Where("draft = ? AND date_schedule BETWEEN ? AND ? AND employee_id IN (?)", true,
+				weekStart.Format("2006-01-02"), weekStart.AddDate(0, 0, 6).Format("2006-01-02"),
+				departmentEmployeeIDs(tx, departmentID)).
+			Update("draft", false)
+		published = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to publish schedules: " + err.Error(),
+		})
+		return
+	}
+
+	models.DB.Preload("Publisher").First(&publication, publication.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":       false,
+		"message":     fmt.Sprintf("%d schedules published", published),
+		"publication": formatSchedulePublication(publication),
+		"published":   published,
+	})
+}
+
+// ListSchedulePublications reports for every week between ?start_date= and ?end_date= (DD-MM-YYYY, default
+// the current and the next three weeks) whether the caller's department published it, how many schedules
+// are still drafts and how many changes were made after publication.
+func ListSchedulePublications(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	startDate := models.WeekStart(time.Now())
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid start_date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		startDate = models.WeekStart(parsed)
+	}
+	endDate := startDate.AddDate(0, 0, 27)
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid end_date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		endDate = models.WeekStart(parsed).AddDate(0, 0, 6)
+	}
+	weekCount := (int(endDate.Sub(startDate).Hours()/24) + 1) / 7
+	if weekCount < 1 || weekCount > maxPublicationWeeks {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": fmt.Sprintf("end_date must be on or after start_date and the range at most %d weeks", maxPublicationWeeks),
+		})
+		return
+	}
+
+	departmentID := manager.Position.DepartmentId
+	from, to := startDate.Format("2006-01-02"), endDate.Format("2006-01-02")
+
+	var publications []models.SchedulePublication
+	if err := models.DB.Preload("Publisher").
+		Where("department_id = ? AND week_start BETWEEN ? AND ?", departmentID, from, to).
+		Find(&publications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load publications: " + err.Error(),
+		})
+		return
+	}
+	publicationsByWeek := make(map[string]models.SchedulePublication, len(publications))
+	for _, publication := range publications {
+		publicationsByWeek[normalizeScheduleDate(publication.WeekStart)] = publication
+	}
+
+	// drafts and changes counted per week
+	var draftDates []string
+	if err := models.DB.Model(&models.Schedule{}).
+		Where("draft = ? AND date_schedule BETWEEN ? AND ? AND employee_id IN (?)", true, from, to,
+			departmentEmployeeIDs(models.DB, departmentID)).
+		Pluck("date_schedule", &draftDates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to count drafts: " + err.Error(),
+		})
+		return
+	}
+	var changeDates []string
+	if err := models.DB.Model(&models.ScheduleChange{}).
+		Where("department_id = ? AND date_schedule BETWEEN ? AND ?", departmentID, from, to).
+		Pluck("date_schedule", &changeDates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to count changes: " + err.Error(),
+		})
+		return
+	}
+	countByWeek := func(dates []string) map[string]int {
+		counts := make(map[string]int)
+		for _, date := range dates {
+			if day, err := time.Parse("2006-01-02", normalizeScheduleDate(date)); err == nil {
+				counts[models.WeekStart(day).Format("2006-01-02")]++
+			}
+		}
+		return counts
+	}
+	drafts, changes := countByWeek(draftDates), countByWeek(changeDates)
+
+	weeks := make([]gin.H, 0)
+	for week := startDate; !week.After(endDate); week = week.AddDate(0, 0, 7) {
+		key := week.Format("2006-01-02")
+		entry := gin.H{
+			"week_start":  week.Format("02-01-2006"),
+			"week_end":    week.AddDate(0, 0, 6).Format("02-01-2006"),
+			"published":   false,
+			"publication": nil,
+			"drafts":      drafts[key],
+			"changes":     changes[key],
+		}
+		if publication, ok := publicationsByWeek[key]; ok {
+			entry["published"] = true
+			entry["publication"] = formatSchedulePublication(publication)
+		}
+		weeks = append(weeks, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Schedule publications retrieved successfully",
+		"weeks":   weeks,
+	})
+}
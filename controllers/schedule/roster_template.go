@@ -0,0 +1,159 @@
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadScheduleManager returns the authenticated employee with their position, templates belong to their department
+func loadScheduleManager(c *gin.Context) (models.Employee, bool) {
+	var employee models.Employee
+
+	employeeID, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return employee, false
+	}
+
+	if err := models.DB.Preload("Position").First(&employee, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return employee, false
+	}
+	return employee, true
+}
+
+// loadRosterTemplate loads a template of the department with its slots
+func loadRosterTemplate(id interface{}, departmentID int) (models.RosterTemplate, error) {
+	var template models.RosterTemplate
+	err := models.DB.
+		Preload("Slots", func(db *gorm.DB) *gorm.DB { return db.Order("weekday, id") }).
+		Preload("Slots.Employee").Preload("Slots.Position").Preload("Slots.Shift").
+		Where("id = ? AND department_id = ?", id, departmentID).
+		First(&template).Error
+	return template, err
+}
+
+// findDepartmentTemplate loads the :id template, answering 404 when it is not in the department
+func findDepartmentTemplate(c *gin.Context, departmentID int) (models.RosterTemplate, bool) {
+	template, err := loadRosterTemplate(c.Param("id"), departmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Roster template not found",
+		})
+		return template, false
+	}
+	return template, true
+}
+
+// buildTemplateSlots validates the slots against the department and returns them ready to save
+func buildTemplateSlots(inputs []RosterTemplateSlotInput, departmentID int) ([]models.RosterTemplateSlot, []gin.H) {
+	var slotErrors []gin.H
+	slots := make([]models.RosterTemplateSlot, 0, len(inputs))
+	seen := make(map[string]bool)
+
+	for i, input := range inputs {
+		weekday, ok := weekdays[strings.ToLower(input.Weekday)]
+		if !ok {
+			slotErrors = append(slotErrors, gin.H{"index": i, "message": fmt.Sprintf("unknown weekday %q", input.Weekday)})
+			continue
+		}
+		if (input.EmployeeID == nil) == (input.PositionID == nil) {
+			slotErrors = append(slotErrors, gin.H{"index": i, "message": "set either employee_id or position_id"})
+			continue
+		}
+
+		var target string
+		if input.EmployeeID != nil {
+			var employee models.Employee
+			if err := models.DB.Preload("Position").First(&employee, *input.EmployeeID).Error; err != nil {
+				slotErrors = append(slotErrors, gin.H{"index": i, "message": "Employee not found"})
+				continue
+			}
+			if employee.Position.DepartmentId != departmentID {
+				slotErrors = append(slotErrors, gin.H{"index": i, "message": "Employee is not in your department"})
+				continue
+			}
+			target = fmt.Sprintf("employee:%d", *input.EmployeeID)
+		} else {
+			var position models.Position
+			if err := models.DB.First(&position, *input.PositionID).Error; err != nil {
+				slotErrors = append(slotErrors, gin.H{"index": i, "message": "Position not found"})
+				continue
+			}
+			if position.DepartmentId != departmentID {
+				slotErrors = append(slotErrors, gin.H{"index": i, "message": "Position is not in your department"})
+				continue
+			}
+			target = fmt.Sprintf("position:%d", *input.PositionID)
+		}
+
+		var shift models.Shift
+		if err := models.DB.First(&shift, input.ShiftID).Error; err != nil {
+			slotErrors = append(slotErrors, gin.H{"index": i, "message": "Shift not found"})
+			continue
+		}
+
+		key := fmt.Sprintf("%d|%s", weekday, target)
+		if seen[key] {
+			slotErrors = append(slotErrors, gin.H{"index": i, "message": "the " + strings.SplitN(target, ":", 2)[0] + " already has a slot on this weekday"})
+			continue
+		}
+		seen[key] = true
+
+		slots = append(slots, models.RosterTemplateSlot{
+			Weekday:    int(weekday),
+			EmployeeID: input.EmployeeID,
+			PositionID: input.PositionID,
+			ShiftID:    input.ShiftID,
+		})
+	}
+	return slots, slotErrors
+}
+
+func formatRosterTemplate(template models.RosterTemplate) gin.H {
+	slots := make([]gin.H, 0, len(template.Slots))
+	for _, slot := range template.Slots {
+		formatted := gin.H{
+			"id":       slot.ID,
+			"weekday":  strings.ToLower(time.Weekday(slot.Weekday).String()),
+			"employee": nil,
+			"position": nil,
+			"shift": gin.H{
+				"id":        slot.Shift.ID,
+				"name":      slot.Shift.Type,
+				"clock_in":  slot.Shift.StartTime,
+				"clock_out": slot.Shift.EndTime,
+			},
+		}
+		if slot.EmployeeID != nil {
+			formatted["employee"] = gin.H{"id": slot.Employee.Id, "name": slot.Employee.Name}
+		}
+		if slot.PositionID != nil {
+			formatted["position"] = gin.H{"id": slot.Position.Id, "name": slot.Position.PositionName}
+		}
+		slots = append(slots, formatted)
+	}
+
+	return gin.H{
+		"id":            template.ID,
+		"name":          template.Name,
+		"department_id": template.DepartmentID,
+		"created_by":    template.CreatedBy,
+		"slots":         slots,
+		"created_at":    template.CreatedAt.Format(time.RFC3339),
+		"updated_at":    template.UpdatedAt.Format(time.RFC3339),
+	}
+}
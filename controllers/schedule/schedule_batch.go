@@ -0,0 +1,283 @@
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// result of a generated row in the batch report
+const (
+	bulkRowCreated  = "created"
+	bulkRowReady    = "ready" // dry run, the row would be created
+	bulkRowConflict = "conflict"
+	bulkRowSkipped  = "skipped"
+	// the row breaks labor rules and was not overridden
+	bulkRowViolation = "violation"
+)
+
+// BulkScheduleRow is the report of one generated schedule
+type BulkScheduleRow struct {
+	EmployeeID   uint   `json:"employee_id"`
+	ShiftID      uint   `json:"shift_id"`
+	DateSchedule string `json:"date_schedule"`
+	Result       string `json:"result"`
+	Reason       string `json:"reason,omitempty"`
+	Holiday      string `json:"holiday,omitempty"`
+	// Draft is set on rows of a week that is not published yet
+	Draft      bool `json:"draft,omitempty"`
+	ScheduleID uint `json:"schedule_id,omitempty"`
+	// Violations are the labor rules broken by the row, kept when they were overridden
+	Violations []models.LaborViolation `json:"violations,omitempty"`
+}
+
+// plannedSchedule is a schedule to generate, used by bulk creation and roster templates.
+// The schedules of an employee are planned in date order so labor rules are checked day after day.
+type plannedSchedule struct {
+	Employee models.Employee
+	ShiftID  uint
+	Date     time.Time
+}
+
+// scheduleBatch is the checked result of a list of planned schedules
+type scheduleBatch struct {
+	rows     []BulkScheduleRow
+	toCreate []models.Schedule
+	// rowIndexes[i] is the index in rows of toCreate[i]
+	rowIndexes []int
+	summary    map[string]int
+	// overrides[i] are the overridden labor rules of toCreate[i]
+	overrides      map[int][]models.LaborViolation
+	overrideReason string
+	departmentID   int
+	creatorID      uint
+}
+
+// planScheduleBatch checks the planned schedules of the department against the employee status, approved
+// leave, existing schedules and labor rules. Rows breaking labor rules are only kept when the override is set.
+// Rows in a week the department has not published yet are created as drafts.
+func planScheduleBatch(planned []plannedSchedule, departmentID int, creatorID uint, status string, override LaborOverride) (*scheduleBatch, error) {
+	batch := &scheduleBatch{
+		summary:        map[string]int{bulkRowCreated: 0, bulkRowConflict: 0, bulkRowSkipped: 0, bulkRowViolation: 0},
+		overrides:      make(map[int][]models.LaborViolation),
+		overrideReason: override.OverrideReason,
+		departmentID:   departmentID,
+		creatorID:      creatorID,
+	}
+	if len(planned) == 0 {
+		return batch, nil
+	}
+
+	employeeIDs := make([]uint, 0, len(planned))
+	shiftIDs := make([]uint, 0, len(planned))
+	startDate, endDate := planned[0].Date, planned[0].Date
+	for _, p := range planned {
+		employeeIDs = append(employeeIDs, uint(p.Employee.Id))
+		shiftIDs = append(shiftIDs, p.ShiftID)
+		if p.Date.Before(startDate) {
+			startDate = p.Date
+		}
+		if p.Date.After(endDate) {
+			endDate = p.Date
+		}
+	}
+
+	// working time of the existing schedules per employee, a day around the range for overnight shifts
+	var existing []models.Schedule
+	if err := models.DB.Preload("Shift").
+		Where("employee_id IN ? AND date_schedule BETWEEN ? AND ?", employeeIDs,
+			startDate.AddDate(0, 0, -1).Format("2006-01-02"), endDate.AddDate(0, 0, 1).Format("2006-01-02")).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	taken := make(map[uint][]models.WorkedShift)
+	for _, schedule := range existing {
+		date, err := time.Parse("2006-01-02", normalizeScheduleDate(schedule.DateSchedule))
+		if err != nil {
+			continue
+		}
+		period, err := models.WorkedShiftOn(date, schedule.Shift)
+		if err != nil {
+			return nil, err
+		}
+		taken[schedule.EmployeeID] = append(taken[schedule.EmployeeID], period)
+	}
+	overlaps := func(employeeID uint, period models.WorkedShift) bool {
+		for _, other := range taken[employeeID] {
+			if period.Overlaps(other) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var leaves []models.LeaveRequest
+	if err := models.DB.Scopes(models.ApprovedLeaveBetween(startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))).
+		Where("employee_id IN ?", employeeIDs).Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+	leavesByEmployee := make(map[uint][]models.LeaveRequest)
+	for _, leave := range leaves {
+		leavesByEmployee[leave.EmployeeID] = append(leavesByEmployee[leave.EmployeeID], leave)
+	}
+	holidays := holidaysBetween(startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	var shifts []models.Shift
+	if err := models.DB.Where("id IN ?", shiftIDs).Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	shiftsByID := make(map[uint]models.Shift, len(shifts))
+	for _, shift := range shifts {
+		shiftsByID[shift.ID] = shift
+	}
+	labor, err := models.NewLaborChecker(models.DB, config.App.Labor, employeeIDs, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	published, err := publishedWeeks(departmentID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	onLeave := func(employeeID uint, date string) bool {
+		for _, leave := range leavesByEmployee[employeeID] {
+			if leave.Covers(date) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range planned {
+		employeeID := uint(p.Employee.Id)
+		dbDate := p.Date.Format("2006-01-02")
+		row := BulkScheduleRow{
+			EmployeeID:   employeeID,
+			ShiftID:      p.ShiftID,
+			DateSchedule: p.Date.Format("02-01-2006"),
+			Holiday:      holidays.Name(dbDate),
+		}
+
+		period, err := models.WorkedShiftOn(p.Date, shiftsByID[p.ShiftID])
+		if err != nil {
+			return nil, err
+		}
+		violations, err := labor.Check(employeeID, p.Date, shiftsByID[p.ShiftID])
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case !p.Employee.IsActiveOn(dbDate):
+			row.Result, row.Reason = bulkRowSkipped, "Employee is not active on this date"
+			batch.summary[bulkRowSkipped]++
+		case onLeave(employeeID, dbDate):
+			row.Result, row.Reason = bulkRowSkipped, "Employee is on approved leave on this date"
+			batch.summary[bulkRowSkipped]++
+		case overlaps(employeeID, period):
+			row.Result, row.Reason = bulkRowConflict, "The shift overlaps another schedule of this employee"
+			batch.summary[bulkRowConflict]++
+		case len(violations) > 0 && !override.OverrideLaborRules:
+			row.Result, row.Reason, row.Violations = bulkRowViolation, "The schedule breaks labor rules", violations
+			batch.summary[bulkRowViolation]++
+		default:
+			row.Result = bulkRowReady
+			row.Draft = !published[models.WeekStart(p.Date).Format("2006-01-02")]
+			if len(violations) > 0 {
+				row.Violations = violations
+				batch.overrides[len(batch.toCreate)] = violations
+			}
+			batch.toCreate = append(batch.toCreate, models.Schedule{
+				EmployeeID:   employeeID,
+				ShiftID:      p.ShiftID,
+				CreatedBy:    creatorID,
+				DateSchedule: dbDate,
+				Status:       status,
+				Draft:        row.Draft,
+			})
+			batch.rowIndexes = append(batch.rowIndexes, len(batch.rows))
+			// a later row overlapping this one conflicts with it
+			taken[employeeID] = append(taken[employeeID], period)
+			if err := labor.Add(employeeID, p.Date, shiftsByID[p.ShiftID]); err != nil {
+				return nil, err
+			}
+		}
+		batch.rows = append(batch.rows, row)
+	}
+	return batch, nil
+}
+
+// saveScheduleBatch writes the batch response. With onConflict "abort" nothing is created when a row
+// conflicts or breaks labor rules, a dry run only reports, otherwise every ready row is created in one
+// transaction together with the audit entries of the overridden labor rules.
+func saveScheduleBatch(c *gin.Context, batch *scheduleBatch, onConflict string, dryRun bool) {
+	if onConflict == "abort" && batch.summary[bulkRowConflict]+batch.summary[bulkRowViolation] > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Some schedules conflict with existing ones or break labor rules, nothing was created",
+			"summary": batch.summary,
+			"rows":    batch.rows,
+		})
+		return
+	}
+
+	if dryRun {
+		batch.summary[bulkRowReady] = len(batch.toCreate)
+		delete(batch.summary, bulkRowCreated)
+		c.JSON(http.StatusOK, gin.H{
+			"error":   false,
+			"message": "Dry run, no schedules were created",
+			"summary": batch.summary,
+			"rows":    batch.rows,
+		})
+		return
+	}
+
+	if len(batch.toCreate) > 0 {
+		err := models.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.CreateInBatches(&batch.toCreate, 200).Error; err != nil {
+				return err
+			}
+			for _, schedule := range batch.toCreate {
+				if schedule.Draft {
+					continue
+				}
+				change := models.NewScheduleChange(schedule, batch.departmentID, models.ScheduleChangeCreated, batch.creatorID)
+				if err := models.RecordScheduleChange(tx, change); err != nil {
+					return err
+				}
+			}
+			for i, violations := range batch.overrides {
+				if err := recordLaborOverride(tx, c, batch.toCreate[i], violations, batch.overrideReason); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to create schedules, nothing was created: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	for i, schedule := range batch.toCreate {
+		batch.rows[batch.rowIndexes[i]].Result = bulkRowCreated
+		batch.rows[batch.rowIndexes[i]].ScheduleID = schedule.ID
+	}
+	batch.summary[bulkRowCreated] = len(batch.toCreate)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": fmt.Sprintf("%d schedules created", len(batch.toCreate)),
+		"summary": batch.summary,
+		"rows":    batch.rows,
+	})
+}
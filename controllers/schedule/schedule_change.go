@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListMyScheduleChanges returns the changes made to the caller's published schedules since ?since=
+// (DD-MM-YYYY, default the last seven days), including schedules given to a colleague
+func ListMyScheduleChanges(c *gin.Context) {
+	employeeID, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return
+	}
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -7)
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.ParseInLocation("02-01-2006", value, now.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid since format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		since = parsed
+	}
+
+	var changes []models.ScheduleChange
+	if err := models.DB.Preload("Employee").Preload("Shift").Preload("Changer").
+		Where("(employee_id = ? OR previous_employee_id = ?) AND created_at >= ?", employeeID, employeeID, since).
+		Order("created_at DESC, id DESC").
+		Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load schedule changes: " + err.Error(),
+		})
+		return
+	}
+
+	result := make([]gin.H, len(changes))
+	for i, change := range changes {
+		result[i] = formatScheduleChange(change)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Schedule changes retrieved successfully",
+		"changes": result,
+	})
+}
+
+// ListDepartmentScheduleChanges returns the changes made after publication to the schedules of the caller's
+// department in the week of ?week_start= (DD-MM-YYYY, default the current week)
+func ListDepartmentScheduleChanges(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	weekStart := models.WeekStart(time.Now())
+	if value := c.Query("week_start"); value != "" {
+		parsed, err := time.Parse("02-01-2006", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Invalid week_start format. Use DD-MM-YYYY",
+			})
+			return
+		}
+		weekStart = models.WeekStart(parsed)
+	}
+	from, to := weekStart.Format("2006-01-02"), weekStart.AddDate(0, 0, 6).Format("2006-01-02")
+
+	var changes []models.ScheduleChange
+	if err := models.DB.Preload("Employee").Preload("Shift").Preload("Changer").
+		Where("department_id = ? AND (date_schedule BETWEEN ? AND ? OR previous_date BETWEEN ? AND ?)",
+			manager.Position.DepartmentId, from, to, from, to).
+		Order("created_at DESC, id DESC").
+		Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to load schedule changes: " + err.Error(),
+		})
+		return
+	}
+
+	result := make([]gin.H, len(changes))
+	for i, change := range changes {
+		result[i] = formatScheduleChange(change)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":      false,
+		"message":    "Schedule changes retrieved successfully",
+		"week_start": weekStart.Format("02-01-2006"),
+		"week_end":   weekStart.AddDate(0, 0, 6).Format("02-01-2006"),
+		"changes":    result,
+	})
+}
@@ -0,0 +1,17 @@
+package schedule
+
+import (
+	"log"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+)
+
+// holidaysBetween loads the holidays used to flag schedule dates, schedules are still returned when it fails
+func holidaysBetween(from, to string) models.HolidayIndex {
+	index, err := models.LoadHolidays(models.DB, from, to)
+	if err != nil {
+		log.Printf("ERROR: failed to load holidays between %s and %s: %v", from, to, err)
+		return models.HolidayIndex{}
+	}
+	return index
+}
@@ -0,0 +1,276 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListStaffingRules returns the staffing rules of the caller's department
+func ListStaffingRules(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	var rules []models.StaffingRule
+	if err := models.DB.Preload("Position").Preload("Shift").
+		Where("department_id = ?", manager.Position.DepartmentId).
+		Order("shift_id, position_id, weekday, id").
+		Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve staffing rules: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, formatStaffingRule(rule))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Staffing rules retrieved successfully",
+		"rules":   data,
+	})
+}
+
+// CreateStaffingRule adds a headcount rule for the caller's department
+func CreateStaffingRule(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	rule, ok := bindStaffingRule(c, manager.Position.DepartmentId, 0)
+	if !ok {
+		return
+	}
+
+	if err := models.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create staffing rule: " + err.Error(),
+		})
+		return
+	}
+	models.DB.Preload("Position").Preload("Shift").First(&rule, rule.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": "Staffing rule created successfully",
+		"rule":    formatStaffingRule(rule),
+	})
+}
+
+// UpdateStaffingRule replaces a staffing rule of the caller's department
+func UpdateStaffingRule(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	existing, ok := findStaffingRule(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	rule, ok := bindStaffingRule(c, manager.Position.DepartmentId, existing.ID)
+	if !ok {
+		return
+	}
+	rule.ID, rule.CreatedAt = existing.ID, existing.CreatedAt
+
+	if err := models.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update staffing rule: " + err.Error(),
+		})
+		return
+	}
+	models.DB.Preload("Position").Preload("Shift").First(&rule, rule.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Staffing rule updated successfully",
+		"rule":    formatStaffingRule(rule),
+	})
+}
+
+// DeleteStaffingRule removes a staffing rule of the caller's department
+func DeleteStaffingRule(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	rule, ok := findStaffingRule(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	if err := models.DB.Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete staffing rule: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Staffing rule deleted successfully",
+	})
+}
+
+// findStaffingRule loads the :id rule, answering 404 when it is not in the department
+func findStaffingRule(c *gin.Context, departmentID int) (models.StaffingRule, bool) {
+	var rule models.StaffingRule
+	if err := models.DB.Where("id = ? AND department_id = ?", c.Param("id"), departmentID).First(&rule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Staffing rule not found",
+		})
+		return rule, false
+	}
+	return rule, true
+}
+
+// bindStaffingRule validates the body against the department. ruleID is the rule being updated,
+// 0 on creation, so it does not count as a duplicate of itself.
+func bindStaffingRule(c *gin.Context, departmentID int, ruleID uint) (models.StaffingRule, bool) {
+	var input StaffingRuleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return models.StaffingRule{}, false
+	}
+
+	rule := models.StaffingRule{
+		DepartmentID: departmentID,
+		PositionID:   input.PositionID,
+		ShiftID:      input.ShiftID,
+		MinStaff:     input.MinStaff,
+		MaxStaff:     input.MaxStaff,
+	}
+
+	if input.Weekday != "" {
+		weekday, ok := weekdays[strings.ToLower(input.Weekday)]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Unknown weekday " + input.Weekday,
+			})
+			return rule, false
+		}
+		day := int(weekday)
+		rule.Weekday = &day
+	}
+
+	if input.MaxStaff != nil && *input.MaxStaff < input.MinStaff {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "max_staff must be greater than or equal to min_staff",
+		})
+		return rule, false
+	}
+	if input.MinStaff == 0 && input.MaxStaff == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Set min_staff or max_staff",
+		})
+		return rule, false
+	}
+
+	if input.PositionID != nil {
+		var position models.Position
+		if err := models.DB.First(&position, *input.PositionID).Error; err != nil || position.DepartmentId != departmentID {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Position is not in your department",
+			})
+			return rule, false
+		}
+	}
+
+	var shift models.Shift
+	if err := models.DB.First(&shift, input.ShiftID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Shift not found",
+		})
+		return rule, false
+	}
+
+	duplicate := models.DB.Model(&models.StaffingRule{}).
+		Where("department_id = ? AND shift_id = ? AND id <> ?", departmentID, input.ShiftID, ruleID)
+	if rule.PositionID != nil {
+		duplicate = duplicate.Where("position_id = ?", *rule.PositionID)
+	} else {
+		duplicate = duplicate.Where("position_id IS NULL")
+	}
+	if rule.Weekday != nil {
+		duplicate = duplicate.Where("weekday = ?", *rule.Weekday)
+	} else {
+		duplicate = duplicate.Where("weekday IS NULL")
+	}
+	var count int64
+	duplicate.Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "A staffing rule already exists for this position, shift and weekday",
+		})
+		return rule, false
+	}
+
+	return rule, true
+}
+
+func formatStaffingRule(rule models.StaffingRule) gin.H {
+	var weekday, position interface{}
+	if rule.Weekday != nil {
+		weekday = strings.ToLower(time.Weekday(*rule.Weekday).String())
+	}
+	if rule.PositionID != nil {
+		position = gin.H{"id": rule.Position.Id, "name": rule.Position.PositionName}
+	}
+
+	return gin.H{
+		"id":       rule.ID,
+		"position": position,
+		"shift": gin.H{
+			"id":        rule.Shift.ID,
+			"name":      rule.Shift.Type,
+			"clock_in":  rule.Shift.StartTime,
+			"clock_out": rule.Shift.EndTime,
+		},
+		"weekday":    weekday,
+		"min_staff":  rule.MinStaff,
+		"max_staff":  rule.MaxStaff,
+		"updated_at": rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
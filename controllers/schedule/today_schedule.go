@@ -36,15 +36,16 @@ func GetTodaySchedule(c *gin.Context) {
 		UpdatedAt    time.Time `json:"updated_at"`
 	}
 
-	var scheduleData ScheduleData
+	var scheduleData []ScheduleData
 
-	// Query to get employee's schedule for today only
+	// Query to get employee's schedules for today only, several shifts a day are ordered by start time
 	query := models.DB.Table("schedules s").
 		Joins("JOIN shifts sh ON s.shift_id = sh.id").
-		Where("s.employee_id = ? AND DATE(s.date_schedule) = ?", 
-			employeeID, today.Format("2006-01-02"))
+		Where("s.employee_id = ? AND s.date_schedule = ? AND s.draft = ?", 
+			employeeID, today.Format("2006-01-02"), false).
+		Order("sh.start_time, s.id")
 
-	// Execute the query and retrieve schedule
+	// Execute the query and retrieve schedules
 	result := query.Select(`
 		s.id, 
 		s.date_schedule, 
@@ -54,19 +55,9 @@ func GetTodaySchedule(c *gin.Context) {
 		sh.end_time as shift_end,
 		s.created_at,
 		s.updated_at
-	`).First(&scheduleData)
+	`).Find(&scheduleData)
 
-	// Check if no schedule found for today
 	if result.Error != nil {
-		if result.Error.Error() == "record not found" {
-			c.JSON(http.StatusOK, gin.H{
-				"error":     false,
-				"message":   "No schedule found for today",
-				"schedule":  nil,
-			})
-			return
-		}
-		
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Error retrieving today's schedule: " + result.Error.Error(),
@@ -74,30 +65,50 @@ func GetTodaySchedule(c *gin.Context) {
 		return
 	}
 
-	// Convert date format if needed
-	dateStr := scheduleData.DateSchedule
-	if t, err := time.Parse("2006-01-02", scheduleData.DateSchedule); err == nil {
-		dateStr = t.Format("02-01-2006") // Format to DD-MM-YYYY
+	// Check if no schedule found for today
+	if len(scheduleData) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"error":     false,
+			"message":   "No schedule found for today",
+			"schedule":  nil,
+			"schedules": []gin.H{},
+		})
+		return
 	}
-	
-	// Format the response
-	schedule := gin.H{
-		"id": scheduleData.ID,
-		"date_schedule": dateStr,
-		"shift": gin.H{
-			"id":         scheduleData.ShiftID,
-			"start_time": scheduleData.ShiftStart,
-			"end_time":   scheduleData.ShiftEnd,
-			"type":       scheduleData.ShiftType,
-		},
-		"created_at": scheduleData.CreatedAt.Format(time.RFC3339),
-		"updated_at": scheduleData.UpdatedAt.Format(time.RFC3339),
+
+	todayDate := today.Format("2006-01-02")
+	holiday := holidaysBetween(todayDate, todayDate).Name(todayDate)
+
+	schedules := make([]gin.H, len(scheduleData))
+	for i, data := range scheduleData {
+		// Convert date format if needed
+		dateStr := data.DateSchedule
+		if t, err := time.Parse("2006-01-02", data.DateSchedule); err == nil {
+			dateStr = t.Format("02-01-2006") // Format to DD-MM-YYYY
+		}
+
+		// Format the response
+		schedules[i] = gin.H{
+			"id": data.ID,
+			"date_schedule": dateStr,
+			"is_holiday":    holiday != "",
+			"holiday_name":  holiday,
+			"shift": gin.H{
+				"id":         data.ShiftID,
+				"start_time": data.ShiftStart,
+				"end_time":   data.ShiftEnd,
+				"type":       data.ShiftType,
+			},
+			"created_at": data.CreatedAt.Format(time.RFC3339),
+			"updated_at": data.UpdatedAt.Format(time.RFC3339),
+		}
 	}
 
-	// Return the response
+	// schedule is the first shift of the day, schedules lists all of them
 	c.JSON(http.StatusOK, gin.H{
 		"error":     false,
 		"message":   "Today's schedule retrieved successfully",
-		"schedule":  schedule,
+		"schedule":  schedules[0],
+		"schedules": schedules,
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,85 @@
+package schedule
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// UpdateRosterTemplate renames a template and replaces all of its slots
+func UpdateRosterTemplate(c *gin.Context) {
+	manager, ok := loadScheduleManager(c)
+	if !ok {
+		return
+	}
+
+	template, ok := findDepartmentTemplate(c, manager.Position.DepartmentId)
+	if !ok {
+		return
+	}
+
+	var input RosterTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Validation failed",
+				"errors":  out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	slots, slotErrors := buildTemplateSlots(input.Slots, manager.Position.DepartmentId)
+	if len(slotErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Validation failed",
+			"errors":  slotErrors,
+		})
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&template).Update("name", input.Name).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("roster_template_id = ?", template.ID).Delete(&models.RosterTemplateSlot{}).Error; err != nil {
+			return err
+		}
+		for i := range slots {
+			slots[i].RosterTemplateID = template.ID
+		}
+		return tx.Create(&slots).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update roster template: " + err.Error(),
+		})
+		return
+	}
+
+	template, _ = loadRosterTemplate(template.ID, manager.Position.DepartmentId)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Roster template updated successfully",
+		"template": formatRosterTemplate(template),
+	})
+}
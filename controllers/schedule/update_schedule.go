@@ -5,10 +5,12 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
 // UpdateSchedule handles PUT /api/schedules/{id}
@@ -32,16 +34,6 @@ func UpdateSchedule(c *gin.Context) {
 		return
 	}
 
-	// Check if employee is manager/supervisor
-	var isManager bool
-	if result := models.DB.Raw("SELECT position_name LIKE '%manager%' OR position_name LIKE '%supervisor%' OR position_name LIKE '%executive%' FROM positions WHERE id = ?", employee.PositionId).Scan(&isManager); result.Error != nil || !isManager {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error":   true,
-			"message": "You don't have permission to update schedules",
-		})
-		return
-	}
-
 	// Get schedule ID from URL parameter
 	scheduleID := c.Param("id")
 	if scheduleID == "" {
@@ -66,6 +58,7 @@ func UpdateSchedule(c *gin.Context) {
 		ShiftID      uint   `json:"shift_id"`
 		DateSchedule string `json:"date_schedule"`
 		Status       string `json:"status"`
+		LaborOverride
 	}
 
 	var request UpdateScheduleRequest
@@ -104,6 +97,10 @@ func UpdateSchedule(c *gin.Context) {
 		return
 	}
 
+	previousDate := schedule.DateSchedule
+	previousShiftID := schedule.ShiftID
+	wasPublished := !schedule.Draft
+
 	// Update shift if provided
 	if request.ShiftID != 0 {
 		var shift models.Shift
@@ -129,25 +126,132 @@ func UpdateSchedule(c *gin.Context) {
 		}
 
 		mysqlFormattedDate := dateSchedule.Format("2006-01-02")
-		
-		// Check if a schedule already exists for this employee on this date (and it's not this schedule)
-		var existingSchedule models.Schedule
-		result := models.DB.Where("employee_id = ? AND date(date_schedule) = ? AND id != ?", 
-			schedule.EmployeeID, mysqlFormattedDate, schedule.ID).First(&existingSchedule)
-		if result.RowsAffected > 0 {
-			c.JSON(http.StatusConflict, gin.H{
+
+		if !schedule.Employee.IsActiveOn(mysqlFormattedDate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Employee is not active on this date",
+			})
+			return
+		}
+
+		onLeave, err := models.IsOnLeave(models.DB, schedule.EmployeeID, mysqlFormattedDate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to check leave: " + err.Error(),
+			})
+			return
+		}
+		if onLeave {
+			c.JSON(http.StatusBadRequest, gin.H{
 				"error":   true,
-				"message": "Another schedule already exists for this employee on this date",
+				"message": "Employee is on approved leave on this date",
 			})
 			return
 		}
-		
+
 		schedule.DateSchedule = mysqlFormattedDate
+		// the schedule moved out of the leave it was marked for
+		schedule.LeaveRequestID = nil
 	}
 
 		schedule.Status = request.Status
-	
-	if err := models.DB.Save(&schedule).Error; err != nil {
+
+	// a moved or changed shift must not overlap the other schedules of the employee and is checked
+	// against the labor rules again
+	violations := []models.LaborViolation{}
+	if request.ShiftID != 0 || request.DateSchedule != "" {
+		var shift models.Shift
+		if err := models.DB.First(&shift, schedule.ShiftID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Shift not found",
+			})
+			return
+		}
+		date, _ := time.Parse("2006-01-02", normalizeScheduleDate(schedule.DateSchedule))
+
+		overlapping, err := models.FindOverlappingSchedule(models.DB, schedule.EmployeeID, date, shift, schedule.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   true,
+				"message": "Failed to check existing schedules: " + err.Error(),
+			})
+			return
+		}
+		if overlapping != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":       true,
+				"message":     "The shift overlaps another schedule of this employee",
+				"schedule_id": overlapping.ID,
+			})
+			return
+		}
+
+		// schedules covered by leave are not worked
+		if schedule.LeaveRequestID == nil {
+			if err := request.LaborOverride.validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   true,
+					"message": err.Error(),
+				})
+				return
+			}
+			violations, err = models.CheckLaborRules(models.DB, config.App.Labor, schedule.EmployeeID, date, shift, schedule.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error":   true,
+					"message": "Failed to check labor rules: " + err.Error(),
+				})
+				return
+			}
+			if len(violations) > 0 && !request.OverrideLaborRules {
+				respondLaborViolations(c, violations)
+				return
+			}
+		}
+	}
+
+	departmentID := schedule.Employee.Position.DepartmentId
+
+	// a schedule moved to another week follows the publication of that week
+	published, err := models.IsWeekPublished(models.DB, departmentID, schedule.DateSchedule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check publication: " + err.Error(),
+		})
+		return
+	}
+	schedule.Draft = !published
+
+	coverageBefore := models.CoverageOn(models.DB, departmentID, previousDate, schedule.DateSchedule)
+
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&schedule).Error; err != nil {
+			return err
+		}
+		// employees are told about changes to schedules they could see or can see now
+		if wasPublished || published {
+			change := models.NewScheduleChange(schedule, departmentID, models.ScheduleChangeUpdated, uint(employee.Id))
+			if normalizeScheduleDate(previousDate) != normalizeScheduleDate(schedule.DateSchedule) {
+				date := normalizeScheduleDate(previousDate)
+				change.PreviousDate = &date
+			}
+			if previousShiftID != schedule.ShiftID {
+				change.PreviousShiftID = &previousShiftID
+			}
+			if err := models.RecordScheduleChange(tx, change); err != nil {
+				return err
+			}
+		}
+		if len(violations) > 0 {
+			return recordLaborOverride(tx, c, schedule, violations, request.OverrideReason)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   true,
 			"message": "Failed to update schedule: " + err.Error(),
@@ -171,6 +275,12 @@ func UpdateSchedule(c *gin.Context) {
 		dateForResponse = t.Format("02-01-2006")
 	}
 
+	holidayDate := completeSchedule.DateSchedule
+	if len(holidayDate) > 10 {
+		holidayDate = holidayDate[:10]
+	}
+	holiday := holidaysBetween(holidayDate, holidayDate).Name(holidayDate)
+
 	// Format response
 	scheduleResponse := gin.H{
 		"id": completeSchedule.ID,
@@ -187,6 +297,9 @@ func UpdateSchedule(c *gin.Context) {
 		},
 		"date_schedule": dateForResponse,
 		"status":        completeSchedule.Status,
+		"draft":         completeSchedule.Draft,
+		"is_holiday":    holiday != "",
+		"holiday_name":  holiday,
 		"created_by": gin.H{
 			"id":   completeSchedule.Creator.Id,
 			"name": completeSchedule.Creator.Name,
@@ -196,8 +309,10 @@ func UpdateSchedule(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"error":    false,
-		"message":  "Schedule updated successfully",
-		"schedule": scheduleResponse,
+		"error":             false,
+		"message":           "Schedule updated successfully",
+		"schedule":          scheduleResponse,
+		"coverage_warnings": models.CoverageWarnings(coverageBefore, models.CoverageOn(models.DB, departmentID, previousDate, schedule.DateSchedule)),
+		"labor_violations":  violations,
 	})
 }
\ No newline at end of file
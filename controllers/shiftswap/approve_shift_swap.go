@@ -0,0 +1,236 @@
+package shiftswap
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findDepartmentSwapRequest loads the :id request for a supervisor of its department
+func findDepartmentSwapRequest(c *gin.Context, supervisor models.Employee) (models.ShiftSwapRequest, bool) {
+	request, ok := findSwapRequest(c)
+	if !ok {
+		return request, false
+	}
+	if request.DepartmentID != supervisor.Position.DepartmentId {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You can only manage shift swaps of your department",
+		})
+		return request, false
+	}
+	if request.RequesterID == uint(supervisor.Id) ||
+		(request.TargetEmployeeID != nil && *request.TargetEmployeeID == uint(supervisor.Id)) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You cannot decide on your own request",
+		})
+		return request, false
+	}
+	return request, true
+}
+
+// ApproveShiftSwap lets the supervisor approve an accepted request, the schedules are exchanged
+// (or handed over for a cover) in the same transaction as the state change
+func ApproveShiftSwap(c *gin.Context) {
+	supervisor, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findDepartmentSwapRequest(c, supervisor)
+	if !ok {
+		return
+	}
+	note, ok := bindSwapNote(c)
+	if !ok {
+		return
+	}
+
+	if request.Status != models.SwapStatusAccepted {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only accepted requests can be approved",
+		})
+		return
+	}
+
+	// both employees must stay within the labor rules with the schedules they take over
+	violations, err := swapLaborViolations(request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to check labor rules: " + err.Error(),
+		})
+		return
+	}
+	if len(violations) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":      true,
+			"message":    "The swap breaks labor rules",
+			"violations": violations,
+		})
+		return
+	}
+	coverageDates := []string{request.RequesterSchedule.DateSchedule}
+	if request.TargetScheduleID != nil {
+		coverageDates = append(coverageDates, request.TargetSchedule.DateSchedule)
+	}
+	coverageBefore := models.CoverageOn(models.DB, request.DepartmentID, coverageDates...)
+
+	var problem error
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		var requester, colleague models.Employee
+		if err := tx.First(&requester, request.RequesterID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&colleague, *request.TargetEmployeeID).Error; err != nil {
+			return err
+		}
+
+		// the schedules may have been edited by a manager since the request was made
+		var requesterSchedule models.Schedule
+		if err := tx.First(&requesterSchedule, request.RequesterScheduleID).Error; err != nil ||
+			requesterSchedule.EmployeeID != request.RequesterID {
+			problem = errors.New("the requester's schedule has changed since the request was made")
+			return problem
+		}
+
+		if request.Type == models.SwapTypeSwap {
+			var targetSchedule models.Schedule
+			if err := tx.First(&targetSchedule, *request.TargetScheduleID).Error; err != nil ||
+				targetSchedule.EmployeeID != *request.TargetEmployeeID {
+				problem = errors.New("the colleague's schedule has changed since the request was made")
+				return problem
+			}
+
+			ignore := []uint{requesterSchedule.ID, targetSchedule.ID}
+			if problem = checkCanWork(tx, requester, targetSchedule, ignore...); problem != nil {
+				return problem
+			}
+			if problem = checkCanWork(tx, colleague, requesterSchedule, ignore...); problem != nil {
+				return problem
+			}
+
+			if err := tx.Model(&targetSchedule).Update("employee_id", requester.Id).Error; err != nil {
+				return err
+			}
+			if err := recordReassignment(tx, targetSchedule, request.DepartmentID, colleague.Id, supervisor.Id); err != nil {
+				return err
+			}
+		} else if problem = checkCanWork(tx, colleague, requesterSchedule); problem != nil {
+			return problem
+		}
+
+		if err := tx.Model(&requesterSchedule).Update("employee_id", colleague.Id).Error; err != nil {
+			return err
+		}
+		if err := recordReassignment(tx, requesterSchedule, request.DepartmentID, requester.Id, supervisor.Id); err != nil {
+			return err
+		}
+
+		return transitionSwap(tx, &request, models.SwapStatusApproved, uintPtr(uint(supervisor.Id)), note,
+			map[string]interface{}{"approved_by": supervisor.Id})
+	})
+	if problem != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": problem.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondSwapError(c, err, "Failed to approve shift swap request")
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request approved, schedules have been updated",
+		"request": formatSwapRequest(request),
+		// the department may be over- or understaffed after the swap, e.g. when the employees have other positions
+		"coverage_warnings": models.CoverageWarnings(coverageBefore, models.CoverageOn(models.DB, request.DepartmentID, coverageDates...)),
+	})
+}
+
+// swapLaborViolations checks the labor rules for the schedules each employee takes over in the request,
+// leaving out the schedule they give away
+func swapLaborViolations(request models.ShiftSwapRequest) ([]models.LaborViolation, error) {
+	var requesterSchedule models.Schedule
+	if err := models.DB.Preload("Shift").First(&requesterSchedule, request.RequesterScheduleID).Error; err != nil {
+		return nil, err
+	}
+	requesterDate, err := time.Parse("2006-01-02", scheduleDate(requesterSchedule))
+	if err != nil {
+		return nil, err
+	}
+
+	var violations []models.LaborViolation
+	if request.Type == models.SwapTypeSwap {
+		var targetSchedule models.Schedule
+		if err := models.DB.Preload("Shift").First(&targetSchedule, *request.TargetScheduleID).Error; err != nil {
+			return nil, err
+		}
+		targetDate, err := time.Parse("2006-01-02", scheduleDate(targetSchedule))
+		if err != nil {
+			return nil, err
+		}
+		found, err := models.CheckLaborRules(models.DB, config.App.Labor, request.RequesterID, targetDate, targetSchedule.Shift, requesterSchedule.ID)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, found...)
+		found, err = models.CheckLaborRules(models.DB, config.App.Labor, *request.TargetEmployeeID, requesterDate, requesterSchedule.Shift, targetSchedule.ID)
+		if err != nil {
+			return nil, err
+		}
+		return append(violations, found...), nil
+	}
+	return models.CheckLaborRules(models.DB, config.App.Labor, *request.TargetEmployeeID, requesterDate, requesterSchedule.Shift)
+}
+
+// RejectShiftSwap lets the supervisor reject a request that is not decided yet
+func RejectShiftSwap(c *gin.Context) {
+	supervisor, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findDepartmentSwapRequest(c, supervisor)
+	if !ok {
+		return
+	}
+	note, ok := bindSwapNote(c)
+	if !ok {
+		return
+	}
+
+	if !request.CanTransitionTo(models.SwapStatusRejected) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "The request is already " + request.Status,
+		})
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		return transitionSwap(tx, &request, models.SwapStatusRejected, uintPtr(uint(supervisor.Id)), note, nil)
+	})
+	if err != nil {
+		respondSwapError(c, err, "Failed to reject shift swap request")
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request rejected",
+		"request": formatSwapRequest(request),
+	})
+}
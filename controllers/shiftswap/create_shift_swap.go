@@ -0,0 +1,198 @@
+package shiftswap
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// CreateShiftSwap lets an employee ask a colleague to swap schedules, or offer their schedule for cover
+func CreateShiftSwap(c *gin.Context) {
+	requester, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	var input CreateShiftSwapInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+
+	var schedule models.Schedule
+	if err := models.DB.Where("id = ? AND employee_id = ? AND draft = ?", input.ScheduleID, requester.Id, false).First(&schedule).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Schedule not found",
+		})
+		return
+	}
+	if scheduleDate(schedule) < today {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": "Past schedules cannot be swapped",
+		})
+		return
+	}
+
+	var open int64
+	models.DB.Model(&models.ShiftSwapRequest{}).
+		Where("status IN ? AND (requester_schedule_id = ? OR target_schedule_id = ?)",
+			[]string{models.SwapStatusPending, models.SwapStatusAccepted}, schedule.ID, schedule.ID).
+		Count(&open)
+	if open > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "This schedule already has an open swap request",
+		})
+		return
+	}
+
+	request := models.ShiftSwapRequest{
+		Type:                input.Type,
+		Status:              models.SwapStatusPending,
+		DepartmentID:        requester.Position.DepartmentId,
+		RequesterID:         uint(requester.Id),
+		RequesterScheduleID: schedule.ID,
+		Reason:              input.Reason,
+		ExpiresOn:           scheduleDate(schedule),
+	}
+
+	switch input.Type {
+	case models.SwapTypeSwap:
+		if input.TargetScheduleID == nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "target_schedule_id is required for a swap",
+			})
+			return
+		}
+
+		var targetSchedule models.Schedule
+		if err := models.DB.Where("draft = ?", false).First(&targetSchedule, *input.TargetScheduleID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Target schedule not found",
+			})
+			return
+		}
+		var colleague models.Employee
+		if err := models.DB.Preload("Position").First(&colleague, targetSchedule.EmployeeID).Error; err != nil ||
+			colleague.Id == requester.Id || colleague.Position.DepartmentId != requester.Position.DepartmentId {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "You can only swap with a colleague in your department",
+			})
+			return
+		}
+		if scheduleDate(targetSchedule) < today {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "Past schedules cannot be swapped",
+			})
+			return
+		}
+
+		// both must be free on the other's date once their own schedules are exchanged
+		ignore := []uint{schedule.ID, targetSchedule.ID}
+		if err := checkCanWork(models.DB, requester, targetSchedule, ignore...); err != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+		if err := checkCanWork(models.DB, colleague, schedule, ignore...); err != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+			return
+		}
+
+		request.TargetEmployeeID = uintPtr(uint(colleague.Id))
+		request.TargetScheduleID = uintPtr(targetSchedule.ID)
+		if scheduleDate(targetSchedule) < request.ExpiresOn {
+			request.ExpiresOn = scheduleDate(targetSchedule)
+		}
+
+	case models.SwapTypeCover:
+		if input.TargetScheduleID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": "target_schedule_id is only used for swaps",
+			})
+			return
+		}
+
+		if input.TargetEmployeeID != nil {
+			var colleague models.Employee
+			if err := models.DB.Preload("Position").First(&colleague, *input.TargetEmployeeID).Error; err != nil ||
+				colleague.Id == requester.Id || colleague.Position.DepartmentId != requester.Position.DepartmentId {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   true,
+					"message": "You can only ask a colleague in your department to cover",
+				})
+				return
+			}
+			if err := checkCanWork(models.DB, colleague, schedule); err != nil {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":   true,
+					"message": err.Error(),
+				})
+				return
+			}
+			request.TargetEmployeeID = uintPtr(uint(colleague.Id))
+		}
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&request).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ShiftSwapEvent{
+			RequestID: request.ID,
+			ToStatus:  models.SwapStatusPending,
+			ActorID:   uintPtr(uint(requester.Id)),
+			Note:      input.Reason,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create shift swap request: " + err.Error(),
+		})
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":   false,
+		"message": "Shift swap request created successfully",
+		"request": formatSwapRequest(request),
+	})
+}
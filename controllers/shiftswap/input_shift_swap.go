@@ -0,0 +1,15 @@
+package shiftswap
+
+type CreateShiftSwapInput struct {
+	Type       string `json:"type" binding:"required,oneof=swap cover"`
+	ScheduleID uint   `json:"schedule_id" binding:"required"`
+	// TargetScheduleID is the colleague's schedule to exchange with, required for swaps
+	TargetScheduleID *uint `json:"target_schedule_id"`
+	// TargetEmployeeID asks a specific colleague to cover, leave it empty to offer the shift to the department
+	TargetEmployeeID *uint  `json:"target_employee_id"`
+	Reason           string `json:"reason" binding:"max=255"`
+}
+
+type ShiftSwapNoteInput struct {
+	Note string `json:"note" binding:"max=255"`
+}
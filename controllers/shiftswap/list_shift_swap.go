@@ -0,0 +1,123 @@
+package shiftswap
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListMyShiftSwaps returns the requests the caller made or received, and open cover offers
+// in their department. Filter with ?status=
+func ListMyShiftSwaps(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	expireStaleSwapRequests()
+
+	query := preloadSwapRequest(models.DB).
+		Where("requester_id = ? OR target_employee_id = ? OR (type = ? AND target_employee_id IS NULL AND department_id = ? AND status = ?)",
+			employee.Id, employee.Id, models.SwapTypeCover, employee.Position.DepartmentId, models.SwapStatusPending)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.ShiftSwapRequest
+	if err := query.Order("created_at DESC, id DESC").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve shift swap requests: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(requests))
+	for _, request := range requests {
+		data = append(data, formatSwapRequest(request))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Shift swap requests retrieved successfully",
+		"requests": data,
+	})
+}
+
+// ListDepartmentShiftSwaps returns the requests of the supervisor's department, filter with ?status=
+func ListDepartmentShiftSwaps(c *gin.Context) {
+	supervisor, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	expireStaleSwapRequests()
+
+	query := preloadSwapRequest(models.DB).Where("department_id = ?", supervisor.Position.DepartmentId)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.ShiftSwapRequest
+	if err := query.Order("created_at DESC, id DESC").Find(&requests).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve shift swap requests: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(requests))
+	for _, request := range requests {
+		data = append(data, formatSwapRequest(request))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Shift swap requests retrieved successfully",
+		"requests": data,
+	})
+}
+
+// GetShiftSwap returns a request with its history to the people involved and department supervisors
+func GetShiftSwap(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+
+	request, ok := findSwapRequest(c)
+	if !ok {
+		return
+	}
+
+	if !canViewSwap(employee, request) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Shift swap request not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request retrieved successfully",
+		"request": formatSwapRequest(request),
+	})
+}
+
+// canViewSwap reports whether the employee is involved in the request, may pick it up, or supervises the department
+func canViewSwap(employee models.Employee, request models.ShiftSwapRequest) bool {
+	id := uint(employee.Id)
+	if request.RequesterID == id || (request.TargetEmployeeID != nil && *request.TargetEmployeeID == id) {
+		return true
+	}
+	if request.DepartmentID != employee.Position.DepartmentId {
+		return false
+	}
+	if request.Type == models.SwapTypeCover && request.TargetEmployeeID == nil {
+		return true
+	}
+	return employee.Position.Role.HasPermission(models.PermissionScheduleRead)
+}
@@ -0,0 +1,188 @@
+package shiftswap
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bindSwapNote reads the optional note of a transition, the body may be empty
+func bindSwapNote(c *gin.Context) (string, bool) {
+	var input ShiftSwapNoteInput
+	if c.Request.ContentLength == 0 {
+		return "", true
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return "", false
+	}
+	return input.Note, true
+}
+
+// AcceptShiftSwap lets the colleague accept a swap, or take over a shift offered for cover
+func AcceptShiftSwap(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findSwapRequest(c)
+	if !ok {
+		return
+	}
+	note, ok := bindSwapNote(c)
+	if !ok {
+		return
+	}
+
+	id := uint(employee.Id)
+	isTarget := request.TargetEmployeeID != nil && *request.TargetEmployeeID == id
+	openCover := request.Type == models.SwapTypeCover && request.TargetEmployeeID == nil &&
+		request.DepartmentID == employee.Position.DepartmentId && request.RequesterID != id
+	if !isTarget && !openCover {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You are not the colleague of this request",
+		})
+		return
+	}
+	if request.Status != models.SwapStatusPending {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only pending requests can be accepted",
+		})
+		return
+	}
+
+	// the colleague's availability may have changed since the request was made
+	var err error
+	if request.Type == models.SwapTypeSwap {
+		err = checkCanWork(models.DB, employee, request.RequesterSchedule, request.RequesterScheduleID, *request.TargetScheduleID)
+	} else {
+		err = checkCanWork(models.DB, employee, request.RequesterSchedule)
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		return transitionSwap(tx, &request, models.SwapStatusAccepted, uintPtr(id), note,
+			map[string]interface{}{"target_employee_id": id})
+	})
+	if err != nil {
+		respondSwapError(c, err, "Failed to accept shift swap request")
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request accepted, waiting for supervisor approval",
+		"request": formatSwapRequest(request),
+	})
+}
+
+// DeclineShiftSwap lets the named colleague turn the request down
+func DeclineShiftSwap(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findSwapRequest(c)
+	if !ok {
+		return
+	}
+	note, ok := bindSwapNote(c)
+	if !ok {
+		return
+	}
+
+	id := uint(employee.Id)
+	if request.TargetEmployeeID == nil || *request.TargetEmployeeID != id {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "You are not the colleague of this request",
+		})
+		return
+	}
+	if request.Status != models.SwapStatusPending {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Only pending requests can be declined",
+		})
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		return transitionSwap(tx, &request, models.SwapStatusRejected, uintPtr(id), note, nil)
+	})
+	if err != nil {
+		respondSwapError(c, err, "Failed to decline shift swap request")
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request declined",
+		"request": formatSwapRequest(request),
+	})
+}
+
+// CancelShiftSwap lets the requester withdraw a request that is not decided yet
+func CancelShiftSwap(c *gin.Context) {
+	employee, ok := loadCurrentEmployee(c)
+	if !ok {
+		return
+	}
+	request, ok := findSwapRequest(c)
+	if !ok {
+		return
+	}
+	note, ok := bindSwapNote(c)
+	if !ok {
+		return
+	}
+
+	id := uint(employee.Id)
+	if request.RequesterID != id {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   true,
+			"message": "Only the requester can cancel this request",
+		})
+		return
+	}
+	if !request.CanTransitionTo(models.SwapStatusCancelled) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "The request is already " + request.Status,
+		})
+		return
+	}
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		return transitionSwap(tx, &request, models.SwapStatusCancelled, uintPtr(id), note, nil)
+	})
+	if err != nil {
+		respondSwapError(c, err, "Failed to cancel shift swap request")
+		return
+	}
+
+	preloadSwapRequest(models.DB).First(&request, request.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Shift swap request cancelled",
+		"request": formatSwapRequest(request),
+	})
+}
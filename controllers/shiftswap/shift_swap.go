@@ -0,0 +1,253 @@
+package shiftswap
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errSwapStateChanged means another request changed the swap between reading and updating it
+var errSwapStateChanged = errors.New("the request was changed in the meantime, reload it and try again")
+
+// loadCurrentEmployee returns the authenticated employee with their position
+func loadCurrentEmployee(c *gin.Context) (models.Employee, bool) {
+	var employee models.Employee
+
+	employeeID, exists := c.Get("employeeId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   true,
+			"message": "Unauthorized access",
+		})
+		return employee, false
+	}
+
+	if err := models.DB.Preload("Position.Role.Permissions").First(&employee, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Employee not found",
+		})
+		return employee, false
+	}
+	return employee, true
+}
+
+// preloadSwapRequest loads everything needed to format a request
+func preloadSwapRequest(db *gorm.DB) *gorm.DB {
+	return db.Preload("Requester").
+		Preload("RequesterSchedule.Shift").
+		Preload("TargetEmployee").
+		Preload("TargetSchedule.Shift").
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// findSwapRequest loads the :id request, answering 404 when it does not exist
+func findSwapRequest(c *gin.Context) (models.ShiftSwapRequest, bool) {
+	expireStaleSwapRequests()
+
+	var request models.ShiftSwapRequest
+	if err := preloadSwapRequest(models.DB).Where("id = ?", c.Param("id")).First(&request).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Shift swap request not found",
+		})
+		return request, false
+	}
+	return request, true
+}
+
+// transitionSwap moves the request to the given state and records the event. The update only
+// applies while the request is still in the state it was read in.
+func transitionSwap(tx *gorm.DB, request *models.ShiftSwapRequest, to string, actorID *uint, note string, updates map[string]interface{}) error {
+	if !request.CanTransitionTo(to) {
+		return fmt.Errorf("a %s request cannot become %s", request.Status, to)
+	}
+
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = to
+
+	result := tx.Model(&models.ShiftSwapRequest{}).Where("id = ? AND status = ?", request.ID, request.Status).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errSwapStateChanged
+	}
+
+	event := models.ShiftSwapEvent{
+		RequestID:  request.ID,
+		FromStatus: request.Status,
+		ToStatus:   to,
+		ActorID:    actorID,
+		Note:       note,
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return err
+	}
+
+	request.Status = to
+	return nil
+}
+
+// expireStaleSwapRequests expires open requests whose schedule date has passed
+func expireStaleSwapRequests() {
+	var stale []models.ShiftSwapRequest
+	today := utils.Now().Format("2006-01-02")
+	if err := models.DB.Where("status IN ? AND expires_on < ?", []string{models.SwapStatusPending, models.SwapStatusAccepted}, today).
+		Find(&stale).Error; err != nil {
+		log.Printf("ERROR: failed to load stale shift swap requests: %v", err)
+		return
+	}
+
+	for i := range stale {
+		err := models.DB.Transaction(func(tx *gorm.DB) error {
+			return transitionSwap(tx, &stale[i], models.SwapStatusExpired, nil, "Schedule date has passed", nil)
+		})
+		if err != nil && !errors.Is(err, errSwapStateChanged) {
+			log.Printf("ERROR: failed to expire shift swap request %d: %v", stale[i].ID, err)
+		}
+	}
+}
+
+// scheduleDate returns the date of a schedule as YYYY-MM-DD
+func scheduleDate(schedule models.Schedule) string {
+	if len(schedule.DateSchedule) > 10 {
+		return schedule.DateSchedule[:10]
+	}
+	return schedule.DateSchedule
+}
+
+// checkCanWork verifies the employee can take the schedule: active and not on leave on its date, and no
+// other schedule overlapping its shift. Schedules listed in ignore are being given away in the same change.
+func checkCanWork(tx *gorm.DB, employee models.Employee, schedule models.Schedule, ignore ...uint) error {
+	date := scheduleDate(schedule)
+	if !employee.IsActiveOn(date) {
+		return fmt.Errorf("%s is not active on %s", employee.Name, formatDate(date))
+	}
+	if onLeave, err := models.IsOnLeave(tx, uint(employee.Id), date); err != nil {
+		return err
+	} else if onLeave {
+		return fmt.Errorf("%s is on leave on %s", employee.Name, formatDate(date))
+	}
+
+	shift := schedule.Shift
+	if shift.ID == 0 {
+		if err := tx.First(&shift, schedule.ShiftID).Error; err != nil {
+			return err
+		}
+	}
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	overlapping, err := models.FindOverlappingSchedule(tx, uint(employee.Id), day, shift, ignore...)
+	if err != nil {
+		return err
+	}
+	if overlapping != nil {
+		return fmt.Errorf("%s already has a schedule overlapping this shift on %s", employee.Name, formatDate(date))
+	}
+	return nil
+}
+
+// recordReassignment adds the change of employee of a published schedule to the schedule change log.
+// The schedule already holds its new employee, previousEmployeeID is the one who gave it away.
+func recordReassignment(tx *gorm.DB, schedule models.Schedule, departmentID int, previousEmployeeID, changedBy int) error {
+	if schedule.Draft {
+		return nil
+	}
+	change := models.NewScheduleChange(schedule, departmentID, models.ScheduleChangeReassigned, uint(changedBy))
+	previous := uint(previousEmployeeID)
+	change.PreviousEmployeeID = &previous
+	return models.RecordScheduleChange(tx, change)
+}
+
+// formatDate turns YYYY-MM-DD into the DD-MM-YYYY format used in responses
+func formatDate(date string) string {
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t.Format("02-01-2006")
+	}
+	return date
+}
+
+func formatSwapSchedule(schedule models.Schedule) gin.H {
+	if schedule.ID == 0 {
+		return nil
+	}
+	return gin.H{
+		"id":            schedule.ID,
+		"employee_id":   schedule.EmployeeID,
+		"date_schedule": formatDate(scheduleDate(schedule)),
+		"shift": gin.H{
+			"id":        schedule.Shift.ID,
+			"name":      schedule.Shift.Type,
+			"clock_in":  schedule.Shift.StartTime,
+			"clock_out": schedule.Shift.EndTime,
+		},
+	}
+}
+
+func formatSwapRequest(request models.ShiftSwapRequest) gin.H {
+	var target gin.H
+	if request.TargetEmployeeID != nil {
+		target = gin.H{"id": request.TargetEmployee.Id, "name": request.TargetEmployee.Name}
+	}
+
+	history := make([]gin.H, 0, len(request.History))
+	for _, event := range request.History {
+		history = append(history, gin.H{
+			"from_status": event.FromStatus,
+			"to_status":   event.ToStatus,
+			"actor_id":    event.ActorID,
+			"note":        event.Note,
+			"created_at":  event.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return gin.H{
+		"id":                 request.ID,
+		"type":               request.Type,
+		"status":             request.Status,
+		"requester":          gin.H{"id": request.Requester.Id, "name": request.Requester.Name},
+		"requester_schedule": formatSwapSchedule(request.RequesterSchedule),
+		"target_employee":    target,
+		"target_schedule":    formatSwapSchedule(request.TargetSchedule),
+		"reason":             request.Reason,
+		"expires_on":         formatDate(request.ExpiresOn),
+		"approved_by":        request.ApprovedBy,
+		"history":            history,
+		"created_at":         request.CreatedAt.Format(time.RFC3339),
+		"updated_at":         request.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// respondSwapError maps errors of a transition to a response
+func respondSwapError(c *gin.Context, err error, message string) {
+	if errors.Is(err, errSwapStateChanged) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   true,
+		"message": message + ": " + err.Error(),
+	})
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
@@ -0,0 +1,12 @@
+package worklocation
+
+type WorkLocationInput struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Address   string   `json:"address" binding:"max=255"`
+	Latitude  *float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	// RadiusMeters is the size of the geofence around the coordinates
+	RadiusMeters float64 `json:"radius_meters" binding:"required,gt=0,max=10000"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
@@ -0,0 +1,180 @@
+package worklocation
+
+import (
+	"errors"
+	"net/http"
+
+	errormessage "github.com/OrryFrasetyo/go-api-hotelqu/controllers/error_message"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListWorkLocations returns every work location
+func ListWorkLocations(c *gin.Context) {
+	var locations []models.WorkLocation
+	if err := models.DB.Order("name").Find(&locations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to retrieve work locations: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":     false,
+		"message":   "Work locations retrieved successfully",
+		"locations": locations,
+	})
+}
+
+// GetWorkLocation returns a work location
+func GetWorkLocation(c *gin.Context) {
+	location, ok := findLocation(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Work location retrieved successfully",
+		"location": location,
+	})
+}
+
+// CreateWorkLocation adds a work location
+func CreateWorkLocation(c *gin.Context) {
+	var input WorkLocationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	location := models.WorkLocation{
+		Name:         input.Name,
+		Address:      input.Address,
+		Latitude:     *input.Latitude,
+		Longitude:    *input.Longitude,
+		RadiusMeters: input.RadiusMeters,
+		Active:       input.Active == nil || *input.Active,
+	}
+	if err := models.DB.Create(&location).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to create work location: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"error":    false,
+		"message":  "Work location created successfully",
+		"location": location,
+	})
+}
+
+// UpdateWorkLocation moves, resizes or switches a work location on or off
+func UpdateWorkLocation(c *gin.Context) {
+	location, ok := findLocation(c)
+	if !ok {
+		return
+	}
+
+	var input WorkLocationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	location.Name, location.Address = input.Name, input.Address
+	location.Latitude, location.Longitude = *input.Latitude, *input.Longitude
+	location.RadiusMeters = input.RadiusMeters
+	if input.Active != nil {
+		location.Active = *input.Active
+	}
+	if err := models.DB.Save(&location).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to update work location: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":    false,
+		"message":  "Work location updated successfully",
+		"location": location,
+	})
+}
+
+// DeleteWorkLocation removes a work location that no department is limited to
+func DeleteWorkLocation(c *gin.Context) {
+	location, ok := findLocation(c)
+	if !ok {
+		return
+	}
+
+	var departments int64
+	if err := models.DB.Model(&models.Department{}).Where("work_location_id = ?", location.ID).Count(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete work location: " + err.Error(),
+		})
+		return
+	}
+	if departments > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   true,
+			"message": "Work location is used by a department, deactivate it or move the department first",
+		})
+		return
+	}
+
+	if err := models.DB.Delete(&location).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   true,
+			"message": "Failed to delete work location: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"error":   false,
+		"message": "Work location deleted successfully",
+	})
+}
+
+// bindJSON binds the request body, answering 400 with the validation errors when it is invalid
+func bindJSON(c *gin.Context, input interface{}) bool {
+	if err := c.ShouldBindJSON(input); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			out := make([]errormessage.ErrorMsg, len(ve))
+			for i, fe := range ve {
+				out[i] = errormessage.ErrorMsg{Field: fe.Field(), Message: errormessage.GetErrorMsg(fe)}
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": out,
+			})
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   true,
+				"message": err.Error(),
+			})
+		}
+		return false
+	}
+	return true
+}
+
+// findLocation loads the :id work location, answering 404 when it does not exist
+func findLocation(c *gin.Context) (models.WorkLocation, bool) {
+	var location models.WorkLocation
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&location).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   true,
+			"message": "Work location not found",
+		})
+		return location, false
+	}
+	return location, true
+}
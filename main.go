@@ -2,108 +2,34 @@ package main
 
 import (
 	"log"
-	"os"
 
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/attendance"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/authentication"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/department"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/employee"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/position"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/schedule"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/shift"
-	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/task" // Tambahkan import untuk task
-	"github.com/OrryFrasetyo/go-api-hotelqu/middlewares"
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
-	"github.com/gin-gonic/gin"
+	"github.com/OrryFrasetyo/go-api-hotelqu/notification"
 )
 
 func main() {
-	router := gin.Default()
-
-	router.Static("/uploads", "./uploads")
-
-	models.ConnectDatabase()
-
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Hello Hotelqu",
-		})
-	})
-
-	// Auth routes
-	router.POST("/api/register", authentication.Register)
-	router.POST("/api/login", authentication.Login)
-
-	// Protected routes (requiring JWT authentication)
-	protected := router.Group("/api")
-	protected.Use(middlewares.JWTAuth())
-	{
-		// User profile route
-		protected.GET("/user", employee.GetProfile)
-		protected.PUT("/user", employee.UpdateProfile)
-		protected.GET("/employees", employee.GetEmployeesForDepartment)
-
-		// schedules endpoints
-		protected.GET("/schedules", schedule.ListSchedules)
-		protected.GET("/schedules/today", schedule.GetTodaySchedule)
-		protected.GET("/schedules/department", schedule.ListDepartmentSchedules)
-		protected.POST("/schedules", schedule.CreateSchedule)
-		protected.PUT("/schedules/:id", schedule.UpdateSchedule)
-		protected.DELETE("/schedules/:id", schedule.DeleteSchedule)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
-		// attendance endpoints
-		protected.POST("/attendance", attendance.CreateAttendance)
-		protected.PUT("/attendance", attendance.UpdateAttendance)
-		protected.GET("/attendance", attendance.GetAttendanceLastThreeDays)
-		protected.GET("/attendance/today", attendance.GetAttendanceToday)
-		protected.GET("/attendance/month", attendance.GetAttendanceThisMonth)
-		protected.GET("/attendance/status", attendance.GetAttendanceByStatus)
+	if err := notification.Setup(cfg.Notifier); err != nil {
+		log.Fatalf("Failed to set up notifier: %v", err)
+	}
 
-		// Task route for employees (accessible by all authenticated users)
-		protected.GET("/task", task.ListTaskEmployee)
-		protected.PUT("/task/:id", task.ChecklistTask)
+	models.ConnectDatabase(cfg.Database)
 
-		// task endpoints (hanya untuk manajer/supervisor)
-		taskRoutes := protected.Group("/tasks")
-		taskRoutes.Use(middlewares.ManagerAuth())
-		{
-			taskRoutes.POST("", task.CreateTask)
-			taskRoutes.GET("/department", task.ListDepartmentTasks)
-			taskRoutes.PUT("/:id", task.UpdateTask)
-			taskRoutes.PUT("/status/:id", task.UpdateTaskStatus)
-			taskRoutes.DELETE("/:id", task.DeleteTask)
-			taskRoutes.PUT("/restore/:id",  task.RestoreTask)
+	// give the configured position full access so the first admin can manage the rest
+	if cfg.Admin.SuperAdminPosition != "" {
+		if err := models.AssignRoleToPosition(cfg.Admin.SuperAdminPosition, models.RoleSuperAdmin); err != nil {
+			log.Fatalf("Failed to assign super admin position: %v", err)
 		}
 	}
 
-	// Department routes
-	router.GET("/api/departments", department.FindDepartments)
-	router.POST("/api/departments", department.StoreDepartment)
-	router.GET("/api/departments/:id", department.FindDepartmentById)
-	router.PUT("/api/departments/:id", department.UpdateDepartment)
-	router.DELETE("/api/departments/:id", department.DeleteDepartment)
-
-	// Position routes
-	router.GET("/api/positions", position.FindPositions)
-	router.POST("/api/positions", position.StorePosition)
-	router.GET("/api/positions/:id", position.FindPositionById)
-	router.PUT("/api/positions/:id", position.UpdatePosition)
-	router.DELETE("/api/positions/:id", position.DeletePosition)
-
-	// Shift routes
-	router.GET("/api/shifts", shift.FindShifts)
-	router.POST("/api/shifts", shift.StoreShift)
-	router.GET("/api/shifts/:id", shift.FindShiftById)
-	router.PUT("/api/shifts/:id", shift.UpdateShift)
-	router.DELETE("/api/shifts/:id", shift.DeleteShift)
-
-	// start server with port 3000
-	// router.Run(":3000")
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000" // Port default jika dijalankan di lokal
-	}
+	router := setupRouter(cfg)
 
-	log.Printf("Server starting on port %s", port)
-	router.Run(":" + port)
+	// start server with port from config (default 3000)
+	log.Printf("Server starting on port %s", cfg.Server.Port)
+	router.Run(":" + cfg.Server.Port)
 }
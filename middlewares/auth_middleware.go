@@ -3,7 +3,9 @@ package middlewares
 import (
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -43,9 +45,43 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
+		// token was logged out before it expired
+		revoked, err := utils.IsAccessTokenRevoked(claims.ID)
+		if err != nil || revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Token has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
+		// token was issued before the employee logged out of all devices
+		var employee models.Employee
+		if err := models.DB.Select("id", "token_version", "must_change_password", "status", "termination_date").First(&employee, claims.Id).Error; err != nil || employee.TokenVersion != claims.Version {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Token has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
+		// deactivated and former employees lose access even with a valid token
+		if !employee.IsActiveOn(time.Now().Format("2006-01-02")) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "Your account is not active",
+			})
+			c.Abort()
+			return
+		}
+
 		// Set employee ID and email to conteks for use by handler
 		c.Set("employeeId", claims.Id)
 		c.Set("employeeEmail", claims.Email)
+		c.Set("tokenClaims", claims)
+		c.Set("mustChangePassword", employee.MustChangePassword)
 
 		c.Next()
 	}
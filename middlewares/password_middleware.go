@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePasswordChanged blocks employees flagged for a forced password change until they set a new one.
+// Must be used after JWTAuth.
+func RequirePasswordChanged() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool("mustChangePassword") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "Password change required, use PUT /api/password",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission is middleware that only lets employees whose position role grants the permission through.
+// Must be used after JWTAuth.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		employeeID, exists := c.Get("employeeId")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   true,
+				"message": "Unauthorized access",
+			})
+			c.Abort()
+			return
+		}
+
+		// load employee with the role of their position
+		var employee models.Employee
+		if err := models.DB.Preload("Position.Role.Permissions").First(&employee, employeeID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   true,
+				"message": "Employee not found",
+			})
+			c.Abort()
+			return
+		}
+
+		if !employee.Position.Role.HasPermission(permission) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   true,
+				"message": "You don't have permission to access this resource",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("employeeRole", employee.Position.Role.Name)
+
+		c.Next()
+	}
+}
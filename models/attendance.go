@@ -2,16 +2,75 @@ package models
 
 import "time"
 
+// selfie review states
+const (
+	PhotoReviewPending  = "pending"
+	PhotoReviewApproved = "approved"
+	PhotoReviewRejected = "rejected"
+)
+
 type Attendance struct {
 	ID             uint      `json:"id" gorm:"primaryKey"`
-	ScheduleID     uint      `json:"schedule_id" gorm:"index"`
+	// ScheduleID is unique, a schedule is checked in to once
+	ScheduleID     uint      `json:"schedule_id" gorm:"uniqueIndex:idx_attendances_schedule_unique"`
 	Schedule       Schedule  `json:"schedule" gorm:"foreignKey:ScheduleID"`
 	Date           string    `json:"date" gorm:"type:date"`
-	ClockIn        string    `json:"clock_in" gorm:"type:varchar(8)"`
-	ClockOut       string    `json:"clock_out" gorm:"type:varchar(8)"`
+	// ClockInAt and ClockOutAt are recorded by the server when the employee checks in and out
+	ClockInAt      *time.Time `json:"clock_in_at"`
+	ClockOutAt     *time.Time `json:"clock_out_at"`
+	// ClientClockIn and ClientClockOut (HH:MM) are the times reported by the device, kept for reference only
+	ClientClockIn  string    `json:"client_clock_in" gorm:"type:varchar(8)"`
+	ClientClockOut string    `json:"client_clock_out" gorm:"type:varchar(8)"`
+	// ClockInDrift and ClockOutDrift are set when the device time was further from the server time than allowed
+	ClockInDrift   bool      `json:"clock_in_drift" gorm:"not null;default:false"`
+	ClockOutDrift  bool      `json:"clock_out_drift" gorm:"not null;default:false"`
 	Duration       string    `json:"duration" gorm:"type:varchar(30)"`
 	ClockInStatus  string    `json:"clock_in_status" gorm:"type:varchar(20)"`
 	ClockOutStatus string    `json:"clock_out_status" gorm:"type:varchar(20)"`
+	// where the employee clocked in and out, with the distance to the work location and the geofence result
+	ClockInLatitude    *float64 `json:"clock_in_latitude"`
+	ClockInLongitude   *float64 `json:"clock_in_longitude"`
+	ClockInAccuracy    *float64 `json:"clock_in_accuracy"`
+	ClockInDistance    *float64 `json:"clock_in_distance"`
+	ClockInLocationID  *uint    `json:"clock_in_location_id"`
+	ClockInGeofence    string   `json:"clock_in_geofence" gorm:"type:varchar(10)"`
+	ClockOutLatitude   *float64 `json:"clock_out_latitude"`
+	ClockOutLongitude  *float64 `json:"clock_out_longitude"`
+	ClockOutAccuracy   *float64 `json:"clock_out_accuracy"`
+	ClockOutDistance   *float64 `json:"clock_out_distance"`
+	ClockOutLocationID *uint    `json:"clock_out_location_id"`
+	ClockOutGeofence   string   `json:"clock_out_geofence" gorm:"type:varchar(10)"`
+	// ClockInKioskID and ClockOutKioskID are the kiosks whose code was scanned
+	ClockInKioskID     *uint    `json:"clock_in_kiosk_id" gorm:"index"`
+	ClockOutKioskID    *uint    `json:"clock_out_kiosk_id" gorm:"index"`
+	// ClockInPhoto and ClockOutPhoto are the URL paths of the selfies taken as proof of the punch
+	ClockInPhoto       *string  `json:"clock_in_photo" gorm:"type:varchar(255)"`
+	ClockOutPhoto      *string  `json:"clock_out_photo" gorm:"type:varchar(255)"`
+	// PhotoReview is pending while a supervisor has not approved or rejected the selfies
+	PhotoReview        string     `json:"photo_review" gorm:"type:varchar(10);index"`
+	PhotoReviewedBy    *int       `json:"photo_reviewed_by"`
+	PhotoReviewedAt    *time.Time `json:"photo_reviewed_at"`
+	PhotoReviewNote    string     `json:"photo_review_note" gorm:"type:varchar(255)"`
+	// IsHoliday marks work on a holiday of an active calendar, for holiday pay rates
+	IsHoliday      bool      `json:"is_holiday"`
+	HolidayName    string    `json:"holiday_name" gorm:"type:varchar(255)"`
 	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-}
\ No newline at end of file
+}
+
+// ClockIn returns the check-in time as HH:MM in server time, empty before the check-in
+func (a Attendance) ClockIn() string {
+	return clockOf(a.ClockInAt)
+}
+
+// ClockOut returns the check-out time as HH:MM in server time, empty before the check-out
+func (a Attendance) ClockOut() string {
+	return clockOf(a.ClockOutAt)
+}
+
+func clockOf(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format("15:04")
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrateLegacyClockTimes fills clock_in_at and clock_out_at of attendance recorded before the server kept
+// timestamps from the old clock_in and clock_out (HH:MM) columns, read as server local time on the attendance date
+func migrateLegacyClockTimes(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&Attendance{}, "clock_in") {
+		return nil
+	}
+
+	type legacyAttendance struct {
+		ID       uint
+		Date     string
+		ClockIn  string
+		ClockOut string
+	}
+	var rows []legacyAttendance
+	if err := db.Table("attendances").Select("id, date, clock_in, clock_out").
+		Where("clock_in_at IS NULL AND clock_in IS NOT NULL AND clock_in <> ''").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		day, err := time.ParseInLocation("2006-01-02", normalizeDate(row.Date), time.Local)
+		if err != nil {
+			continue
+		}
+		in, err := ParseShiftClock(row.ClockIn)
+		if err != nil {
+			continue
+		}
+		clockIn := day.Add(in)
+		updates := map[string]interface{}{"clock_in_at": clockIn, "client_clock_in": row.ClockIn}
+		if out, err := ParseShiftClock(row.ClockOut); err == nil {
+			clockOut := day.Add(out)
+			// a check-out before the check-in was on the next day
+			if clockOut.Before(clockIn) {
+				clockOut = clockOut.AddDate(0, 0, 1)
+			}
+			updates["clock_out_at"] = clockOut
+			updates["client_clock_out"] = row.ClockOut
+		}
+		if err := db.Table("attendances").Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateDuplicateAttendance prepares the unique index on attendances.schedule_id. Double punches made
+// before it existed are removed, per schedule the first closed attendance (or else the first one) is kept.
+func migrateDuplicateAttendance(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Attendance{}) || migrator.HasIndex(&Attendance{}, "idx_attendances_schedule_unique") {
+		return nil
+	}
+
+	var rows []struct {
+		ID         uint
+		ScheduleID uint
+		ClockOutAt *time.Time
+	}
+	if err := db.Table("attendances").Select("id, schedule_id, clock_out_at").
+		Where("schedule_id IN (?)", db.Table("attendances").Select("schedule_id").Group("schedule_id").Having("COUNT(*) > 1")).
+		Order("schedule_id, id").Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	keep := make(map[uint]uint)
+	closed := make(map[uint]bool)
+	for _, row := range rows {
+		if _, ok := keep[row.ScheduleID]; !ok || (!closed[row.ScheduleID] && row.ClockOutAt != nil) {
+			keep[row.ScheduleID] = row.ID
+			closed[row.ScheduleID] = row.ClockOutAt != nil
+		}
+	}
+	var remove []uint
+	for _, row := range rows {
+		if keep[row.ScheduleID] != row.ID {
+			remove = append(remove, row.ID)
+		}
+	}
+	if len(remove) > 0 {
+		log.Printf("Removing %d duplicate attendance records", len(remove))
+		if err := db.Where("id IN ?", remove).Delete(&Attendance{}).Error; err != nil {
+			return err
+		}
+	}
+
+	// the unique index replaces the plain one
+	if migrator.HasIndex(&Attendance{}, "idx_attendances_schedule_id") {
+		return migrator.DropIndex(&Attendance{}, "idx_attendances_schedule_id")
+	}
+	return nil
+}
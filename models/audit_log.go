@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// audit actions
+const (
+	AuditLoginLockout = "login.lockout"
+	AuditLoginUnlock  = "login.unlock"
+
+	AuditEmployeeDeactivate = "employee.deactivate"
+	AuditEmployeeTerminate  = "employee.terminate"
+	AuditEmployeeReactivate = "employee.reactivate"
+	AuditEmployeeUpdate     = "employee.update"
+
+	AuditScheduleLaborOverride = "schedule.labor_override"
+
+	AuditKioskRegister     = "kiosk.register"
+	AuditKioskRotateSecret = "kiosk.rotate_secret"
+	AuditKioskReissueToken = "kiosk.reissue_token"
+	AuditKioskDelete       = "kiosk.delete"
+)
+
+// AuditLog records security relevant events. ActorID is empty for events raised by the system.
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Action     string    `json:"action" gorm:"type:varchar(50);not null;index"`
+	ActorID    *int      `json:"actor_id" gorm:"index"`
+	EmployeeID *int      `json:"employee_id" gorm:"index"`
+	IPAddress  string    `json:"ip_address" gorm:"type:varchar(45)"`
+	Detail     string    `json:"detail" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// RecordAudit stores an audit entry
+func RecordAudit(db *gorm.DB, entry AuditLog) error {
+	return db.Create(&entry).Error
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CalendarFeedToken lets calendar apps read the schedule feeds of an employee without a bearer token.
+// An employee has at most one, only the sha256 hash is stored and deleting it revokes the feed URLs.
+type CalendarFeedToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	EmployeeID int        `json:"employee_id" gorm:"uniqueIndex;not null"`
+	Employee   Employee   `json:"-" gorm:"foreignKey:EmployeeID"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
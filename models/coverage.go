@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// staffing status of a coverage slot
+const (
+	CoverageOK           = "ok"
+	CoverageUnderstaffed = "understaffed"
+	CoverageOverstaffed  = "overstaffed"
+)
+
+// CoverageSlot is the staffing of one rule on one date
+type CoverageSlot struct {
+	Date         string `json:"date"`
+	ShiftID      uint   `json:"shift_id"`
+	ShiftName    string `json:"shift_name"`
+	PositionID   *int   `json:"position_id"`
+	PositionName string `json:"position_name,omitempty"`
+	RuleID       uint   `json:"rule_id"`
+	MinStaff     int    `json:"min_staff"`
+	MaxStaff     *int   `json:"max_staff"`
+	Scheduled    int    `json:"scheduled"`
+	Status       string `json:"status"`
+}
+
+// deviation is how far the slot is from its limits, 0 when it is staffed correctly
+func (s CoverageSlot) deviation() int {
+	if s.Scheduled < s.MinStaff {
+		return s.MinStaff - s.Scheduled
+	}
+	if s.MaxStaff != nil && s.Scheduled > *s.MaxStaff {
+		return s.Scheduled - *s.MaxStaff
+	}
+	return 0
+}
+
+// ComputeCoverage evaluates the staffing rules of the department on every date between from and to.
+// Schedules covered by approved leave do not count. A shiftID of 0 checks every shift.
+func ComputeCoverage(db *gorm.DB, departmentID int, from, to time.Time, shiftID uint) ([]CoverageSlot, error) {
+	query := db.Preload("Position").Preload("Shift").Where("department_id = ?", departmentID)
+	if shiftID != 0 {
+		query = query.Where("shift_id = ?", shiftID)
+	}
+	var rules []StaffingRule
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return []CoverageSlot{}, nil
+	}
+
+	type scheduledRow struct {
+		DateSchedule string
+		ShiftID      uint
+		PositionID   int
+	}
+	var rows []scheduledRow
+	if err := db.Table("schedules s").
+		Joins("JOIN employees e ON e.id = s.employee_id").
+		Joins("JOIN positions p ON p.id = e.position_id").
+		Where("p.department_id = ? AND s.date_schedule BETWEEN ? AND ? AND s.leave_request_id IS NULL",
+			departmentID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Select("s.date_schedule, s.shift_id, e.position_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// counts per date, shift and position, the position "*" counts every position
+	counts := make(map[string]int)
+	for _, row := range rows {
+		date := normalizeDate(row.DateSchedule)
+		counts[fmt.Sprintf("%s|%d|%d", date, row.ShiftID, row.PositionID)]++
+		counts[fmt.Sprintf("%s|%d|*", date, row.ShiftID)]++
+	}
+
+	slots := []CoverageSlot{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+
+		// a weekday rule replaces the everyday rule of the same position and shift
+		effective := make(map[string]StaffingRule)
+		for _, rule := range rules {
+			if !rule.AppliesOn(day.Weekday()) {
+				continue
+			}
+			key := fmt.Sprintf("%d|*", rule.ShiftID)
+			if rule.PositionID != nil {
+				key = fmt.Sprintf("%d|%d", rule.ShiftID, *rule.PositionID)
+			}
+			if current, ok := effective[key]; ok && current.Weekday != nil {
+				continue
+			}
+			effective[key] = rule
+		}
+
+		for key, rule := range effective {
+			slot := CoverageSlot{
+				Date:       day.Format("02-01-2006"),
+				ShiftID:    rule.ShiftID,
+				ShiftName:  rule.Shift.Type,
+				PositionID: rule.PositionID,
+				RuleID:     rule.ID,
+				MinStaff:   rule.MinStaff,
+				MaxStaff:   rule.MaxStaff,
+				Scheduled:  counts[date+"|"+key],
+				Status:     CoverageOK,
+			}
+			if rule.PositionID != nil {
+				slot.PositionName = rule.Position.PositionName
+			}
+			if slot.Scheduled < slot.MinStaff {
+				slot.Status = CoverageUnderstaffed
+			} else if slot.deviation() > 0 {
+				slot.Status = CoverageOverstaffed
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Date != slots[j].Date {
+			a, _ := time.Parse("02-01-2006", slots[i].Date)
+			b, _ := time.Parse("02-01-2006", slots[j].Date)
+			return a.Before(b)
+		}
+		if slots[i].ShiftID != slots[j].ShiftID {
+			return slots[i].ShiftID < slots[j].ShiftID
+		}
+		return slots[i].RuleID < slots[j].RuleID
+	})
+	return slots, nil
+}
+
+// CoverageOn returns the coverage of the department on the YYYY-MM-DD dates, used to compare
+// before and after a schedule change. Errors are logged, the change itself is not affected.
+func CoverageOn(db *gorm.DB, departmentID int, dates ...string) []CoverageSlot {
+	var slots []CoverageSlot
+	seen := make(map[string]bool)
+	for _, date := range dates {
+		date = normalizeDate(date)
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil || seen[date] {
+			continue
+		}
+		seen[date] = true
+
+		daySlots, err := ComputeCoverage(db, departmentID, day, day, 0)
+		if err != nil {
+			log.Printf("ERROR: failed to compute coverage of department %d on %s: %v", departmentID, date, err)
+			continue
+		}
+		slots = append(slots, daySlots...)
+	}
+	return slots
+}
+
+// CoverageWarnings returns the slots a change left understaffed or overstaffed, or made worse
+func CoverageWarnings(before, after []CoverageSlot) []CoverageSlot {
+	previous := make(map[string]CoverageSlot, len(before))
+	for _, slot := range before {
+		previous[fmt.Sprintf("%s|%d", slot.Date, slot.RuleID)] = slot
+	}
+
+	warnings := []CoverageSlot{}
+	for _, slot := range after {
+		if slot.deviation() == 0 {
+			continue
+		}
+		if old, ok := previous[fmt.Sprintf("%s|%d", slot.Date, slot.RuleID)]; ok && old.deviation() >= slot.deviation() {
+			continue
+		}
+		warnings = append(warnings, slot)
+	}
+	return warnings
+}
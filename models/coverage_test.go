@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+)
+
+// setupTestDB connects a fresh SQLite database
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	ConnectDatabase(cfg.Database)
+}
+
+func TestComputeCoverageWeekdayRule(t *testing.T) {
+	setupTestDB(t)
+	department := Department{DepartmentName: "Front Office"}
+	DB.Create(&department)
+	receptionist := Position{DepartmentId: department.Id, PositionName: "Receptionist"}
+	DB.Create(&receptionist)
+	shift := Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	DB.Create(&shift)
+	for i := 1; i <= 3; i++ {
+		DB.Create(&Employee{PositionId: receptionist.Id, Name: fmt.Sprint("staff ", i), Email: fmt.Sprintf("staff%d@example.com", i),
+			Password: "-", Phone: "1", Status: EmployeeStatusActive})
+	}
+
+	monday := int(time.Monday)
+	maxStaff := 2
+	everyday := StaffingRule{DepartmentID: department.Id, PositionID: &receptionist.Id, ShiftID: shift.ID, MinStaff: 1, MaxStaff: &maxStaff}
+	mondays := StaffingRule{DepartmentID: department.Id, PositionID: &receptionist.Id, ShiftID: shift.ID, Weekday: &monday, MinStaff: 3}
+	DB.Create(&everyday)
+	DB.Create(&mondays)
+
+	// two receptionists on Monday 2 and Tuesday 3 March 2099, the second one on leave on Tuesday
+	leaveID := uint(1)
+	for _, schedule := range []Schedule{
+		{EmployeeID: 1, DateSchedule: "2099-03-02"},
+		{EmployeeID: 2, DateSchedule: "2099-03-02"},
+		{EmployeeID: 1, DateSchedule: "2099-03-03"},
+		{EmployeeID: 2, DateSchedule: "2099-03-03", LeaveRequestID: &leaveID},
+		{EmployeeID: 1, DateSchedule: "2099-03-04"},
+		{EmployeeID: 2, DateSchedule: "2099-03-04"},
+		{EmployeeID: 3, DateSchedule: "2099-03-04"},
+	} {
+		schedule.ShiftID, schedule.CreatedBy = shift.ID, 1
+		DB.Create(&schedule)
+	}
+
+	cases := []struct {
+		name      string
+		date      string
+		rule      uint
+		scheduled int
+		status    string
+	}{
+		{"weekday rule replaces the everyday rule", "2099-03-02", mondays.ID, 2, CoverageUnderstaffed},
+		{"leave does not count", "2099-03-03", everyday.ID, 1, CoverageOK},
+		{"above the maximum", "2099-03-04", everyday.ID, 3, CoverageOverstaffed},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			day, _ := time.Parse("2006-01-02", tc.date)
+			slots, err := ComputeCoverage(DB, department.Id, day, day, 0)
+			if err != nil {
+				t.Fatalf("compute coverage: %v", err)
+			}
+			if len(slots) != 1 {
+				t.Fatalf("got %d slots, want 1 (%+v)", len(slots), slots)
+			}
+			slot := slots[0]
+			if slot.RuleID != tc.rule || slot.Scheduled != tc.scheduled || slot.Status != tc.status {
+				t.Errorf("slot = rule %d, %d scheduled, %s, want rule %d, %d scheduled, %s",
+					slot.RuleID, slot.Scheduled, slot.Status, tc.rule, tc.scheduled, tc.status)
+			}
+		})
+	}
+}
+
+func TestCoverageWarnings(t *testing.T) {
+	maxStaff := 2
+	slot := func(rule uint, min, scheduled int) CoverageSlot {
+		return CoverageSlot{Date: "02-03-2099", RuleID: rule, MinStaff: min, MaxStaff: &maxStaff, Scheduled: scheduled}
+	}
+
+	cases := []struct {
+		name          string
+		before, after []CoverageSlot
+		want          int
+	}{
+		{"still staffed", []CoverageSlot{slot(1, 1, 2)}, []CoverageSlot{slot(1, 1, 1)}, 0},
+		{"left understaffed", []CoverageSlot{slot(1, 1, 1)}, []CoverageSlot{slot(1, 1, 0)}, 1},
+		{"left overstaffed", []CoverageSlot{slot(1, 1, 2)}, []CoverageSlot{slot(1, 1, 3)}, 1},
+		{"understaffed but improved", []CoverageSlot{slot(1, 3, 1)}, []CoverageSlot{slot(1, 3, 2)}, 0},
+		{"already understaffed", []CoverageSlot{slot(1, 3, 1)}, []CoverageSlot{slot(1, 3, 1)}, 0},
+		{"new rule understaffed", nil, []CoverageSlot{slot(2, 1, 0)}, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CoverageWarnings(tc.before, tc.after); len(got) != tc.want {
+				t.Errorf("got %d warnings, want %d (%+v)", len(got), tc.want, got)
+			}
+		})
+	}
+}
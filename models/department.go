@@ -4,4 +4,12 @@ type Department struct {
 	Id                 int    `json:"id" gorm:"primary_key"`
 	ParentDepartmentId *int   `json:"parent_department_id" gorm:"index"`
 	DepartmentName     string `json:"department_name"`
+	// WorkLocationID limits the geofence to one location, every active location counts when nil
+	WorkLocationID *uint `json:"work_location_id" gorm:"index"`
+	// GeofencePolicy decides what happens to punches outside the geofence: off, flag or reject
+	GeofencePolicy string `json:"geofence_policy" gorm:"type:varchar(10);not null;default:'off'"`
+	// KioskRequired only accepts check-ins and check-outs with the code of a kiosk
+	KioskRequired bool `json:"kiosk_required" gorm:"not null;default:false"`
+	// SelfieRequired only accepts check-ins and check-outs with a selfie photo
+	SelfieRequired bool `json:"selfie_required" gorm:"not null;default:false"`
 }
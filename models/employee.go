@@ -1,6 +1,9 @@
 package models
 
 import (
+	"sync"
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -14,11 +17,85 @@ type Employee struct {
 	Password   string   `json:"-" gorm:"type:varchar(255);not null"`
 	Photo      *string  `json:"photo" gorm:"type:varchar(100)"`
 	Phone      string   `json:"phone" gorm:"type:varchar(16);not null"`
+	// TokenVersion is embedded in access tokens, incrementing it logs the employee out of every device
+	TokenVersion int `json:"-" gorm:"not null;default:0"`
+	// Status controls whether the employee may log in, self registrations start as pending
+	Status     string     `json:"status" gorm:"type:varchar(20);not null;default:'active';index"`
+	ReviewedBy *int       `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	ReviewNote string     `json:"review_note" gorm:"type:varchar(255)"`
+	// MustChangePassword blocks every endpoint except changing the password until it is cleared
+	MustChangePassword bool `json:"must_change_password" gorm:"not null;default:false"`
+	// TerminationDate (YYYY-MM-DD) is the last working day of a terminated employee
+	TerminationDate *string    `json:"termination_date" gorm:"type:date"`
+	StatusReason    string     `json:"status_reason" gorm:"type:varchar(255)"`
+	StatusChangedBy *int       `json:"status_changed_by"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+}
+
+// employee account status values
+const (
+	EmployeeStatusPending  = "pending"
+	EmployeeStatusActive   = "active"
+	EmployeeStatusRejected = "rejected"
+	EmployeeStatusDisabled = "disabled"
+	// terminated employees keep working until their termination date
+	EmployeeStatusTerminated = "terminated"
+)
+
+// IsActiveOn reports whether the employee may log in and be scheduled on the given date (YYYY-MM-DD)
+func (e Employee) IsActiveOn(date string) bool {
+	switch e.Status {
+	case EmployeeStatusActive:
+		return true
+	case EmployeeStatusTerminated:
+		return e.TerminationDate != nil && date <= normalizeDate(*e.TerminationDate)
+	}
+	return false
 }
 
+// ActiveOn is a query scope selecting the employees that are active on the given date (YYYY-MM-DD)
+func ActiveOn(date string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("employees.status = ? OR (employees.status = ? AND employees.termination_date >= ?)",
+			EmployeeStatusActive, EmployeeStatusTerminated, date)
+	}
+}
+
+// CanManageEmployee reports whether the actor may change the account of the target, the role of the
+// target's position must not grant permissions the actor does not hold (see Role.CanGrant)
+func CanManageEmployee(db *gorm.DB, actorID, targetID int) (bool, error) {
+	var actor, target Employee
+	if err := db.Preload("Position.Role.Permissions").First(&actor, actorID).Error; err != nil {
+		return false, err
+	}
+	if err := db.Preload("Position.Role.Permissions").First(&target, targetID).Error; err != nil {
+		return false, err
+	}
+	return actor.Position.Role.CanGrant(target.Position.Role), nil
+}
+
+// normalizeDate cuts a date column read back as a timestamp (e.g. "2025-04-03T00:00:00Z") to YYYY-MM-DD
+func normalizeDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// passwordHashCost is the bcrypt cost of stored passwords
+const passwordHashCost = 14
+
+// dummyPasswordHash is compared against when no employee matches a login, hashed once with the same cost
+// as real passwords
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), passwordHashCost)
+	return hash
+})
+
 // HashPassword converts plain text passwords into bcrypt hashes
 func (e *Employee) HashPassword(password string) error {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
 	if err != nil {
 		return err
 	}
@@ -31,6 +108,12 @@ func (e *Employee) CheckPassword(password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(e.Password), []byte(password))
 }
 
+// CheckDummyPassword takes as long as CheckPassword without an employee, so a login with an unknown email
+// cannot be told apart from a wrong password by its response time
+func CheckDummyPassword(password string) {
+	bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+}
+
 // BeforeCreate is a GORM hook that is executed before insert
 func (e *Employee) BeforeCreate(tx *gorm.DB) error {
 	// if password not null dan not been hash, do hash
@@ -38,4 +121,4 @@ func (e *Employee) BeforeCreate(tx *gorm.DB) error {
 		return e.HashPassword(e.Password)
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HolidayCalendar groups holidays, e.g. the national holidays or the property's own dates.
+// Only the holidays of active calendars are applied to schedules and attendance.
+type HolidayCalendar struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	Active      bool      `json:"active"`
+	Holidays    []Holiday `json:"holidays,omitempty" gorm:"foreignKey:CalendarID"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Holiday is a one-off date, or a date repeated every year from Date on when Recurring is set
+type Holiday struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	CalendarID uint   `json:"calendar_id" gorm:"index"`
+	Name       string `json:"name" gorm:"type:varchar(255);not null"`
+	Date       string `json:"date" gorm:"type:date;index"`
+	Recurring  bool   `json:"recurring"`
+	// UID is the identifier of the iCalendar event the holiday was imported from
+	UID       string    `json:"uid,omitempty" gorm:"type:varchar(255);index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// HolidayIndex maps YYYY-MM-DD dates to the names of the holidays on that date
+type HolidayIndex map[string][]string
+
+// Name returns the holidays on the date joined by commas, empty when it is a regular day
+func (h HolidayIndex) Name(date string) string {
+	return strings.Join(h[normalizeDate(date)], ", ")
+}
+
+// LoadHolidays returns the holidays of the active calendars between the YYYY-MM-DD dates, both included
+func LoadHolidays(db *gorm.DB, from, to string) (HolidayIndex, error) {
+	var holidays []Holiday
+	err := db.Joins("JOIN holiday_calendars ON holiday_calendars.id = holidays.calendar_id").
+		Where("holiday_calendars.active = ?", true).
+		Where("(holidays.recurring = ? OR holidays.date BETWEEN ? AND ?)", true, from, to).
+		Order("holidays.date, holidays.id").
+		Find(&holidays).Error
+	if err != nil {
+		return nil, err
+	}
+
+	index := HolidayIndex{}
+	start, errStart := time.Parse("2006-01-02", from)
+	end, errEnd := time.Parse("2006-01-02", to)
+	for _, holiday := range holidays {
+		date := normalizeDate(holiday.Date)
+		if !holiday.Recurring {
+			index[date] = append(index[date], holiday.Name)
+			continue
+		}
+
+		first, err := time.Parse("2006-01-02", date)
+		if err != nil || errStart != nil || errEnd != nil {
+			continue
+		}
+		for year := start.Year(); year <= end.Year(); year++ {
+			if year < first.Year() {
+				continue
+			}
+			day := time.Date(year, first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+			// 29 February only exists in leap years
+			if day.Month() != first.Month() {
+				continue
+			}
+			if key := day.Format("2006-01-02"); key >= from && key <= to {
+				index[key] = append(index[key], holiday.Name)
+			}
+		}
+	}
+	return index, nil
+}
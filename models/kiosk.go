@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// KioskDevice is a shared terminal that shows a rotating code employees scan to prove they clock in at it.
+// The kiosk authenticates with a device token (only its hash is stored), the codes are signed with Secret.
+type KioskDevice struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"type:varchar(100);not null"`
+	// DepartmentID limits the kiosk to the employees of one department, anyone may use it when nil
+	DepartmentID *int `json:"department_id" gorm:"index"`
+	// WorkLocationID is where the kiosk stands, a valid code counts as a punch inside its geofence
+	WorkLocationID  *uint      `json:"work_location_id" gorm:"index"`
+	TokenHash       string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	Secret          string     `json:"-" gorm:"type:varchar(64);not null"`
+	SecretRotatedAt time.Time  `json:"secret_rotated_at"`
+	Active          bool       `json:"active"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
+	CreatedBy       int        `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// KioskCodeUse records that an employee used the code of a kiosk period, a code is accepted only once so
+// it cannot be passed on to a colleague
+type KioskCodeUse struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	KioskID    uint      `json:"kiosk_id" gorm:"uniqueIndex:idx_kiosk_code_once"`
+	Counter    int64     `json:"counter" gorm:"uniqueIndex:idx_kiosk_code_once"`
+	EmployeeID int       `json:"employee_id" gorm:"index"`
+	UsedAt     time.Time `json:"used_at" gorm:"index"`
+}
+
+// migrateKioskCodeUses drops the old index that accepted a kiosk code once per employee, so the index
+// accepting it only once can be created. Uses only matter for a minute, the old ones are cleared.
+func migrateKioskCodeUses(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&KioskCodeUse{}) || !migrator.HasIndex(&KioskCodeUse{}, "idx_kiosk_code_use") {
+		return nil
+	}
+	if err := migrator.DropIndex(&KioskCodeUse{}, "idx_kiosk_code_use"); err != nil {
+		return err
+	}
+	return db.Where("1 = 1").Delete(&KioskCodeUse{}).Error
+}
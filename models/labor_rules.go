@@ -0,0 +1,200 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"gorm.io/gorm"
+)
+
+// labor rules checked when a schedule is created or moved
+const (
+	LaborRuleMinRest            = "min_rest"
+	LaborRuleMaxWeeklyHours     = "max_weekly_hours"
+	LaborRuleMaxConsecutiveDays = "max_consecutive_days"
+)
+
+// LaborViolation is a labor rule broken by a schedule. Limit and Actual are hours for the
+// rest and weekly rules and days for the consecutive days rule.
+type LaborViolation struct {
+	Rule       string  `json:"rule"`
+	EmployeeID uint    `json:"employee_id"`
+	Date       string  `json:"date"`
+	Limit      float64 `json:"limit"`
+	Actual     float64 `json:"actual"`
+	Message    string  `json:"message"`
+}
+
+// WorkedShift is a shift of an employee with its real start and end
+type WorkedShift struct {
+	Date       string // YYYY-MM-DD
+	Start, End time.Time
+}
+
+// WorkedShiftOn places a shift on a date
+func WorkedShiftOn(date time.Time, shift Shift) (WorkedShift, error) {
+	start, end, err := shift.Period(date)
+	if err != nil {
+		return WorkedShift{}, err
+	}
+	return WorkedShift{Date: date.Format("2006-01-02"), Start: start, End: end}, nil
+}
+
+// Hours is the length of the shift
+func (w WorkedShift) Hours() float64 {
+	return w.End.Sub(w.Start).Hours()
+}
+
+// Overlaps reports whether the two shifts share working time
+func (w WorkedShift) Overlaps(other WorkedShift) bool {
+	return w.Start.Before(other.End) && other.Start.Before(w.End)
+}
+
+// LaborChecker holds the worked shifts of employees around the checked dates.
+// Planned schedules are checked and then added one at a time, so a batch is checked against itself.
+type LaborChecker struct {
+	rules  config.LaborConfig
+	shifts map[uint][]WorkedShift
+}
+
+// NewLaborChecker loads the schedules of the employees around from and to. Schedules covered by
+// approved leave are not worked, ignore leaves out the schedules being changed.
+func NewLaborChecker(db *gorm.DB, rules config.LaborConfig, employeeIDs []uint, from, to time.Time, ignore ...uint) (*LaborChecker, error) {
+	checker := &LaborChecker{rules: rules, shifts: make(map[uint][]WorkedShift)}
+
+	// enough days around the range to see the whole week and the longest allowed run of working days
+	margin := rules.MaxConsecutiveDays
+	if margin < 7 {
+		margin = 7
+	}
+	query := db.Preload("Shift").
+		Where("employee_id IN ? AND date_schedule BETWEEN ? AND ? AND leave_request_id IS NULL", employeeIDs,
+			from.AddDate(0, 0, -margin-1).Format("2006-01-02"), to.AddDate(0, 0, margin+1).Format("2006-01-02"))
+	if len(ignore) > 0 {
+		query = query.Where("id NOT IN ?", ignore)
+	}
+	var schedules []Schedule
+	if err := query.Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	for _, schedule := range schedules {
+		date, err := time.Parse("2006-01-02", normalizeDate(schedule.DateSchedule))
+		if err != nil {
+			continue
+		}
+		if err := checker.Add(schedule.EmployeeID, date, schedule.Shift); err != nil {
+			return nil, err
+		}
+	}
+	return checker, nil
+}
+
+// Add records a shift of the employee as worked
+func (lc *LaborChecker) Add(employeeID uint, date time.Time, shift Shift) error {
+	period, err := WorkedShiftOn(date, shift)
+	if err != nil {
+		return err
+	}
+	shifts := append(lc.shifts[employeeID], period)
+	sort.Slice(shifts, func(i, j int) bool { return shifts[i].Start.Before(shifts[j].Start) })
+	lc.shifts[employeeID] = shifts
+	return nil
+}
+
+// Check returns the labor rules broken when the employee works the shift on the date
+func (lc *LaborChecker) Check(employeeID uint, date time.Time, shift Shift) ([]LaborViolation, error) {
+	period, err := WorkedShiftOn(date, shift)
+	if err != nil {
+		return nil, err
+	}
+	shifts := lc.shifts[employeeID]
+	displayDate := date.Format("02-01-2006")
+	violations := []LaborViolation{}
+
+	if minRest := lc.rules.MinRest.Duration; minRest > 0 {
+		for _, other := range shifts {
+			// the parts of a split shift are one working day
+			if other.Date == period.Date {
+				continue
+			}
+			// rest before or after the other shift, negative when they overlap
+			rest := other.Start.Sub(period.End)
+			if other.Start.Before(period.Start) {
+				rest = period.Start.Sub(other.End)
+			}
+			if rest >= minRest {
+				continue
+			}
+			otherDate, _ := time.Parse("2006-01-02", other.Date)
+			violations = append(violations, LaborViolation{
+				Rule:       LaborRuleMinRest,
+				EmployeeID: employeeID,
+				Date:       displayDate,
+				Limit:      minRest.Hours(),
+				Actual:     rest.Hours(),
+				Message: fmt.Sprintf("Only %gh of rest between this shift and the shift on %s, the minimum is %gh",
+					rest.Hours(), otherDate.Format("02-01-2006"), minRest.Hours()),
+			})
+		}
+	}
+
+	if maxHours := lc.rules.MaxWeeklyHours; maxHours > 0 {
+		weekStart := WeekStart(date)
+		weekEnd := weekStart.AddDate(0, 0, 7)
+		hours := period.Hours()
+		for _, other := range shifts {
+			if !other.Start.Before(weekStart) && other.Start.Before(weekEnd) {
+				hours += other.Hours()
+			}
+		}
+		if hours > maxHours {
+			violations = append(violations, LaborViolation{
+				Rule:       LaborRuleMaxWeeklyHours,
+				EmployeeID: employeeID,
+				Date:       displayDate,
+				Limit:      maxHours,
+				Actual:     hours,
+				Message: fmt.Sprintf("%gh scheduled in the week starting %s, the maximum is %gh",
+					hours, weekStart.Format("02-01-2006"), maxHours),
+			})
+		}
+	}
+
+	if maxDays := lc.rules.MaxConsecutiveDays; maxDays > 0 {
+		worked := make(map[string]bool, len(shifts))
+		for _, other := range shifts {
+			worked[other.Date] = true
+		}
+		run := 1
+		for day := date.AddDate(0, 0, -1); worked[day.Format("2006-01-02")]; day = day.AddDate(0, 0, -1) {
+			run++
+		}
+		for day := date.AddDate(0, 0, 1); worked[day.Format("2006-01-02")]; day = day.AddDate(0, 0, 1) {
+			run++
+		}
+		if run > maxDays {
+			violations = append(violations, LaborViolation{
+				Rule:       LaborRuleMaxConsecutiveDays,
+				EmployeeID: employeeID,
+				Date:       displayDate,
+				Limit:      float64(maxDays),
+				Actual:     float64(run),
+				Message:    fmt.Sprintf("%d working days in a row, the maximum is %d", run, maxDays),
+			})
+		}
+	}
+
+	return violations, nil
+}
+
+// CheckLaborRules checks a single schedule of an employee, ignore leaves out the schedule being changed
+func CheckLaborRules(db *gorm.DB, rules config.LaborConfig, employeeID uint, date time.Time, shift Shift, ignore ...uint) ([]LaborViolation, error) {
+	checker, err := NewLaborChecker(db, rules, []uint{employeeID}, date, date, ignore...)
+	if err != nil {
+		return nil, err
+	}
+	return checker.Check(employeeID, date, shift)
+}
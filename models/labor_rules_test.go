@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+)
+
+func TestLaborCheckerRules(t *testing.T) {
+	rules := config.LaborConfig{
+		MinRest:            config.Duration{Duration: 11 * time.Hour},
+		MaxWeeklyHours:     40,
+		MaxConsecutiveDays: 6,
+	}
+	morning := Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	evening := Shift{Type: "Evening", StartTime: "15:00", EndTime: "23:00"}
+	breakfast := Shift{Type: "Breakfast", StartTime: "06:00", EndTime: "10:00"}
+	dinner := Shift{Type: "Dinner", StartTime: "17:00", EndTime: "21:00"}
+
+	type worked struct {
+		date  string
+		shift Shift
+	}
+	// days from Monday 2 March 2099 on
+	week := func(shift Shift, days int) []worked {
+		var shifts []worked
+		for i := 0; i < days; i++ {
+			shifts = append(shifts, worked{fmt.Sprintf("2099-03-%02d", 2+i), shift})
+		}
+		return shifts
+	}
+
+	cases := []struct {
+		name     string
+		rules    config.LaborConfig
+		existing []worked
+		date     string
+		shift    Shift
+		want     []string
+	}{
+		{"enough rest", rules, []worked{{"2099-03-02", morning}}, "2099-03-03", morning, nil},
+		{"too little rest after an evening", rules, []worked{{"2099-03-02", evening}}, "2099-03-03", morning,
+			[]string{LaborRuleMinRest}},
+		{"too little rest before an evening", rules, []worked{{"2099-03-03", morning}}, "2099-03-02", evening,
+			[]string{LaborRuleMinRest}},
+		{"split shift on one day", rules, []worked{{"2099-03-02", breakfast}}, "2099-03-02", dinner, nil},
+		{"forty hours in a week", rules, week(morning, 4), "2099-03-06", morning, nil},
+		{"more than forty hours in a week", rules, week(morning, 5), "2099-03-07", morning,
+			[]string{LaborRuleMaxWeeklyHours}},
+		{"hours of the previous week do not count", rules, week(morning, 5), "2099-03-09", morning, nil},
+		{"six days in a row", rules, week(breakfast, 5), "2099-03-07", breakfast, nil},
+		{"seven days in a row", rules, week(breakfast, 6), "2099-03-08", breakfast,
+			[]string{LaborRuleMaxConsecutiveDays}},
+		{"a day off breaks the run", rules, append(week(breakfast, 5), worked{"2099-03-08", breakfast}), "2099-03-09", breakfast, nil},
+		{"disabled rules", config.LaborConfig{}, week(evening, 7), "2099-03-09", morning, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &LaborChecker{rules: tc.rules, shifts: make(map[uint][]WorkedShift)}
+			for _, w := range tc.existing {
+				date, _ := time.Parse("2006-01-02", w.date)
+				if err := checker.Add(1, date, w.shift); err != nil {
+					t.Fatalf("add: %v", err)
+				}
+			}
+			date, _ := time.Parse("2006-01-02", tc.date)
+			violations, err := checker.Check(1, date, tc.shift)
+			if err != nil {
+				t.Fatalf("check: %v", err)
+			}
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.Rule)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("violations = %v, want %v (%v)", got, tc.want, violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// codes of the built-in leave types
+const (
+	LeaveTypeAnnual    = "annual"
+	LeaveTypeSick      = "sick"
+	LeaveTypeUnpaid    = "unpaid"
+	LeaveTypeMaternity = "maternity"
+)
+
+// leave request states
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+// LeaveType is a kind of time off, DefaultDays is the yearly entitlement of a new balance
+type LeaveType struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Code        string `json:"code" gorm:"type:varchar(30);uniqueIndex;not null"`
+	Name        string `json:"name" gorm:"type:varchar(100);not null"`
+	DefaultDays int    `json:"default_days"`
+	// TracksBalance is false for leave that is not limited per year, such as unpaid leave
+	TracksBalance bool      `json:"tracks_balance"`
+	Paid          bool      `json:"paid"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// LeaveBalance is the entitlement of an employee for a leave type in a year
+type LeaveBalance struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	EmployeeID  uint      `json:"employee_id" gorm:"uniqueIndex:idx_leave_balance"`
+	LeaveTypeID uint      `json:"leave_type_id" gorm:"uniqueIndex:idx_leave_balance"`
+	LeaveType   LeaveType `json:"-" gorm:"foreignKey:LeaveTypeID"`
+	Year        int       `json:"year" gorm:"uniqueIndex:idx_leave_balance"`
+	Entitled    int       `json:"entitled"`
+	Used        int       `json:"used"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Remaining returns the days left once approved leave is deducted
+func (b LeaveBalance) Remaining() int {
+	return b.Entitled - b.Used
+}
+
+// LeaveRequest is a request for time off between two dates, both included
+type LeaveRequest struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	EmployeeID   uint       `json:"employee_id" gorm:"index"`
+	Employee     Employee   `json:"-" gorm:"foreignKey:EmployeeID"`
+	DepartmentID int        `json:"department_id" gorm:"index"`
+	LeaveTypeID  uint       `json:"leave_type_id" gorm:"index"`
+	LeaveType    LeaveType  `json:"-" gorm:"foreignKey:LeaveTypeID"`
+	StartDate    string     `json:"start_date" gorm:"type:date;index"`
+	EndDate      string     `json:"end_date" gorm:"type:date;index"`
+	Days         int        `json:"days"`
+	Reason       string     `json:"reason" gorm:"type:varchar(255)"`
+	Status       string     `json:"status" gorm:"type:varchar(20);index;default:'pending'"`
+	DecidedBy    *uint      `json:"decided_by"`
+	DecidedAt    *time.Time `json:"decided_at"`
+	DecisionNote string     `json:"decision_note" gorm:"type:varchar(255)"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Covers reports whether the request includes the YYYY-MM-DD date
+func (r LeaveRequest) Covers(date string) bool {
+	date = normalizeDate(date)
+	return normalizeDate(r.StartDate) <= date && date <= normalizeDate(r.EndDate)
+}
+
+// ApprovedLeaveBetween selects the approved leave overlapping the YYYY-MM-DD range
+func ApprovedLeaveBetween(from, to string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND start_date <= ? AND end_date >= ?", LeaveStatusApproved, to, from)
+	}
+}
+
+// IsOnLeave reports whether the employee has approved leave on the YYYY-MM-DD date
+func IsOnLeave(db *gorm.DB, employeeID uint, date string) (bool, error) {
+	var count int64
+	err := db.Model(&LeaveRequest{}).Scopes(ApprovedLeaveBetween(date, date)).
+		Where("employee_id = ?", employeeID).Count(&count).Error
+	return count > 0, err
+}
+
+var defaultLeaveTypes = []LeaveType{
+	{Code: LeaveTypeAnnual, Name: "Annual leave", DefaultDays: 12, TracksBalance: true, Paid: true},
+	{Code: LeaveTypeSick, Name: "Sick leave", DefaultDays: 14, TracksBalance: true, Paid: true},
+	{Code: LeaveTypeUnpaid, Name: "Unpaid leave", DefaultDays: 0, TracksBalance: false, Paid: false},
+	{Code: LeaveTypeMaternity, Name: "Maternity leave", DefaultDays: 90, TracksBalance: true, Paid: true},
+}
+
+// seedLeaveTypes creates the built-in leave types, existing ones are left untouched
+func seedLeaveTypes(db *gorm.DB) error {
+	for _, t := range defaultLeaveTypes {
+		var leaveType LeaveType
+		if err := db.Where(LeaveType{Code: t.Code}).Attrs(t).FirstOrCreate(&leaveType).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// login throttle kinds, an account is identified by the lower-cased email and an ip by the client address
+const (
+	LoginThrottleAccount = "account"
+	LoginThrottleIP      = "ip"
+)
+
+// LoginThrottle counts failed logins for an account or ip address. It lives in the database
+// so every API instance sees the same counters and lockouts.
+type LoginThrottle struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Kind         string     `json:"kind" gorm:"type:varchar(10);not null;uniqueIndex:idx_login_throttle_identifier"`
+	Identifier   string     `json:"identifier" gorm:"type:varchar(255);not null;uniqueIndex:idx_login_throttle_identifier"`
+	Failures     int        `json:"failures" gorm:"not null;default:0"`
+	LockCount    int        `json:"lock_count" gorm:"not null;default:0"`
+	LastFailedAt *time.Time `json:"last_failed_at"`
+	LockedUntil  *time.Time `json:"locked_until" gorm:"index"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsLocked reports whether the lockout is still running at the given time
+func (t LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PasswordResetToken is a single-use code sent to the employee to set a new password.
+// Only the sha256 hash is stored.
+type PasswordResetToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	EmployeeID int        `json:"employee_id" gorm:"index"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
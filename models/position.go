@@ -4,6 +4,8 @@ type Position struct {
 	Id           int     `json:"id" gorm:"primary_key"`
 	DepartmentId int        `json:"department_id" gorm:"index"`
 	Department   Department `json:"department" gorm:"foreignKey:DepartmentId"`
+	RoleId       *int       `json:"role_id" gorm:"index"`
+	Role         Role       `json:"-" gorm:"foreignKey:RoleId"`
 	PositionName string     `json:"position_name"`
 	IsCompleted  bool       `json:"is_completed"`
 }
\ No newline at end of file
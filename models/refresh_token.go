@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// RefreshToken is a long lived token used to get a new access token. Only the sha256 hash is stored.
+type RefreshToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	EmployeeID int        `json:"employee_id" gorm:"index"`
+	TokenHash  string     `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"index"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ReplacedBy *uint      `json:"replaced_by"`
+	UserAgent  string     `json:"user_agent" gorm:"type:varchar(255)"`
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45)"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// RevokedToken lists access tokens (by jti) that were logged out before they expired
+type RevokedToken struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	JTI        string    `json:"jti" gorm:"type:varchar(64);uniqueIndex;not null"`
+	EmployeeID int       `json:"employee_id" gorm:"index"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,69 @@
+package models
+
+// permission names checked by middlewares.RequirePermission
+const (
+	PermissionScheduleRead    = "schedule:read"
+	PermissionScheduleWrite   = "schedule:write"
+	PermissionTaskWrite       = "task:write"
+	PermissionMasterDataWrite = "master_data:write"
+	PermissionRoleManage      = "role:manage"
+	PermissionEmployeeManage  = "employee:manage"
+	PermissionLeaveApprove    = "leave:approve"
+	// PermissionAttendanceReview shows the punches and selfies of the department
+	PermissionAttendanceReview = "attendance:review"
+)
+
+// built-in role names, every position is linked to one of these (or a custom role)
+const (
+	RoleEmployee          = "employee"
+	RoleSupervisor        = "supervisor"
+	RoleDepartmentManager = "department_manager"
+	RoleHRAdmin           = "hr_admin"
+	RoleSuperAdmin        = "super_admin"
+)
+
+type Permission struct {
+	Id          int    `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string `json:"description" gorm:"type:varchar(255)"`
+}
+
+type Role struct {
+	Id          int          `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string       `json:"description" gorm:"type:varchar(255)"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+}
+
+// HasPermission reports whether the role grants the given permission
+func (r Role) HasPermission(name string) bool {
+	for _, permission := range r.Permissions {
+		if permission.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionNames returns the names of the permissions granted to the role
+func (r Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// CanGrant reports whether an employee with this role may link other to a position. Role managers may hand
+// out any role, everyone else only roles that grant nothing they do not hold themselves.
+func (r Role) CanGrant(other Role) bool {
+	if r.HasPermission(PermissionRoleManage) {
+		return true
+	}
+	for _, permission := range other.Permissions {
+		if !r.HasPermission(permission.Name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var defaultPermissions = []Permission{
+	{Name: PermissionScheduleRead, Description: "View department schedules"},
+	{Name: PermissionScheduleWrite, Description: "Create, update and delete department schedules"},
+	{Name: PermissionTaskWrite, Description: "Create, update, delete and restore department tasks"},
+	{Name: PermissionMasterDataWrite, Description: "Manage departments, positions and shifts"},
+	{Name: PermissionRoleManage, Description: "Manage roles and their permissions"},
+	{Name: PermissionEmployeeManage, Description: "Manage employee accounts and sessions"},
+	{Name: PermissionLeaveApprove, Description: "Approve and reject leave requests of the department"},
+	{Name: PermissionAttendanceReview, Description: "Review attendance punches and selfies of the department"},
+}
+
+var defaultRoles = []struct {
+	Name        string
+	Description string
+	Permissions []string
+}{
+	{RoleEmployee, "Regular staff", nil},
+	{RoleSupervisor, "Supervises staff in a department", []string{
+		PermissionScheduleRead, PermissionScheduleWrite, PermissionTaskWrite, PermissionLeaveApprove, PermissionAttendanceReview,
+	}},
+	{RoleDepartmentManager, "Manages a department", []string{
+		PermissionScheduleRead, PermissionScheduleWrite, PermissionTaskWrite, PermissionLeaveApprove, PermissionAttendanceReview,
+	}},
+	{RoleHRAdmin, "Human resources administrator", []string{
+		PermissionScheduleRead, PermissionMasterDataWrite, PermissionEmployeeManage,
+	}},
+	// super_admin always receives every permission, see seedRoles
+	{RoleSuperAdmin, "Full access", nil},
+}
+
+// position name keywords used before roles existed, only used to backfill positions without a role
+var legacyManagerKeywords = []string{"manager", "supervisor", "chief", "executive", "director", "sous", "partie"}
+
+// seedRoles creates the default permissions and roles. Permissions of an existing role are
+// left untouched, except that newly introduced permissions are granted to the roles listing them.
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		permissionsByName := make(map[string]Permission)
+		newPermissions := make(map[string]bool)
+		var allPermissions []Permission
+
+		for _, p := range defaultPermissions {
+			var permission Permission
+			result := tx.Where(Permission{Name: p.Name}).Attrs(Permission{Description: p.Description}).FirstOrCreate(&permission)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				newPermissions[permission.Name] = true
+			}
+			permissionsByName[permission.Name] = permission
+			allPermissions = append(allPermissions, permission)
+		}
+
+		for _, r := range defaultRoles {
+			var role Role
+			result := tx.Where(Role{Name: r.Name}).Attrs(Role{Description: r.Description}).FirstOrCreate(&role)
+			if result.Error != nil {
+				return result.Error
+			}
+			roleCreated := result.RowsAffected > 0
+
+			var grant []Permission
+			if r.Name == RoleSuperAdmin {
+				grant = allPermissions
+			} else {
+				for _, name := range r.Permissions {
+					if roleCreated || newPermissions[name] {
+						grant = append(grant, permissionsByName[name])
+					}
+				}
+			}
+
+			if len(grant) > 0 {
+				if err := tx.Model(&role).Association("Permissions").Append(grant); err != nil {
+					return err
+				}
+			}
+		}
+
+		return backfillPositionRoles(tx)
+	})
+}
+
+// backfillPositionRoles links positions that have no role yet, keeping the old behaviour where
+// manager-like position names could manage tasks and schedules
+func backfillPositionRoles(tx *gorm.DB) error {
+	var positions []Position
+	if err := tx.Where("role_id IS NULL").Find(&positions).Error; err != nil {
+		return err
+	}
+	if len(positions) == 0 {
+		return nil
+	}
+
+	var employeeRole, supervisorRole Role
+	if err := tx.Where("name = ?", RoleEmployee).First(&employeeRole).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("name = ?", RoleSupervisor).First(&supervisorRole).Error; err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+		roleId := employeeRole.Id
+		positionName := strings.ToLower(position.PositionName)
+		for _, keyword := range legacyManagerKeywords {
+			if strings.Contains(positionName, keyword) {
+				roleId = supervisorRole.Id
+				break
+			}
+		}
+		if err := tx.Model(&Position{}).Where("id = ?", position.Id).Update("role_id", roleId).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindRoleByName returns the role with the given name
+func FindRoleByName(name string) (Role, error) {
+	var role Role
+	err := DB.Where("name = ?", name).First(&role).Error
+	return role, err
+}
+
+// AssignRoleToPosition links the position with the given name to the named role
+func AssignRoleToPosition(positionName, roleName string) error {
+	role, err := FindRoleByName(roleName)
+	if err != nil {
+		return fmt.Errorf("role %q: %w", roleName, err)
+	}
+
+	// RowsAffected cannot tell a missing position from one that already has the role (MySQL counts
+	// changed rows only), so look the position up first
+	var position Position
+	if err := DB.Where("position_name = ?", positionName).First(&position).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("position %q not found", positionName)
+		}
+		return err
+	}
+	return DB.Model(&Position{}).Where("position_name = ?", positionName).Update("role_id", role.Id).Error
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RosterTemplate is a saved weekly layout of shift assignments for a department
+type RosterTemplate struct {
+	ID           uint                 `json:"id" gorm:"primaryKey"`
+	Name         string               `json:"name" gorm:"type:varchar(100);not null"`
+	DepartmentID int                  `json:"department_id" gorm:"index"`
+	Department   Department           `json:"-" gorm:"foreignKey:DepartmentID"`
+	CreatedBy    uint                 `json:"created_by" gorm:"index"`
+	Slots        []RosterTemplateSlot `json:"slots" gorm:"foreignKey:RosterTemplateID"`
+	CreatedAt    time.Time            `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time            `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// RosterTemplateSlot assigns a shift on a weekday to one employee or to every employee of a position
+type RosterTemplateSlot struct {
+	ID               uint `json:"id" gorm:"primaryKey"`
+	RosterTemplateID uint `json:"roster_template_id" gorm:"index"`
+	// Weekday follows time.Weekday, 0 is Sunday
+	Weekday    int      `json:"weekday" gorm:"not null"`
+	EmployeeID *uint    `json:"employee_id" gorm:"index"`
+	Employee   Employee `json:"-" gorm:"foreignKey:EmployeeID"`
+	PositionID *int     `json:"position_id" gorm:"index"`
+	Position   Position `json:"-" gorm:"foreignKey:PositionID"`
+	ShiftID    uint     `json:"shift_id"`
+	Shift      Shift    `json:"-" gorm:"foreignKey:ShiftID"`
+}
@@ -2,6 +2,12 @@ package models
 
 import "time"
 
+// schedule statuses set by the system, managers may still type other values
+const (
+	ScheduleStatusPresent = "hadir"
+	ScheduleStatusLeave   = "cuti"
+)
+
 type Schedule struct {
 	ID           uint      `json:"id" gorm:"primaryKey"`
 	EmployeeID   uint      `json:"employee_id" gorm:"index"`
@@ -12,6 +18,12 @@ type Schedule struct {
 	Creator      Employee  `json:"creator" gorm:"foreignKey:CreatedBy"`
 	DateSchedule string  		`json:"date_schedule" gorm:"type:date"`
 	Status       string    `json:"status" gorm:"type:varchar(20);default:'hadir'"`
+	// LeaveRequestID is set while the schedule is covered by approved leave
+	LeaveRequestID *uint     `json:"leave_request_id" gorm:"index"`
+	// StatusBeforeLeave is restored when the leave is cancelled
+	StatusBeforeLeave string `json:"-" gorm:"type:varchar(20)"`
+	// Draft schedules are only visible to managers until the week is published
+	Draft        bool      `json:"draft" gorm:"not null;default:false;index"`
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// changes recorded on schedules of a published week
+const (
+	ScheduleChangeCreated    = "created"
+	ScheduleChangeUpdated    = "updated"
+	ScheduleChangeDeleted    = "deleted"
+	ScheduleChangeReassigned = "reassigned"
+)
+
+// SchedulePublication marks a week (Monday to Sunday) of a department as published. Its schedules are
+// visible to employees and every later change is recorded as a ScheduleChange.
+type SchedulePublication struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	DepartmentID int       `json:"department_id" gorm:"not null;uniqueIndex:idx_schedule_publication"`
+	WeekStart    string    `json:"week_start" gorm:"type:date;not null;uniqueIndex:idx_schedule_publication"`
+	PublishedBy  uint      `json:"published_by"`
+	Publisher    Employee  `json:"-" gorm:"foreignKey:PublishedBy"`
+	PublishedAt  time.Time `json:"published_at"`
+}
+
+// ScheduleChange is a change made to a schedule after its week was published.
+// The previous values are set when the date, shift or employee changed.
+type ScheduleChange struct {
+	ID                 uint      `json:"id" gorm:"primaryKey"`
+	ScheduleID         uint      `json:"schedule_id" gorm:"index"`
+	DepartmentID       int       `json:"department_id" gorm:"index"`
+	EmployeeID         uint      `json:"employee_id" gorm:"index"`
+	Employee           Employee  `json:"-" gorm:"foreignKey:EmployeeID"`
+	Action             string    `json:"action" gorm:"type:varchar(20);not null"`
+	DateSchedule       string    `json:"date_schedule" gorm:"type:date;index"`
+	ShiftID            uint      `json:"shift_id"`
+	Shift              Shift     `json:"-" gorm:"foreignKey:ShiftID"`
+	Status             string    `json:"status" gorm:"type:varchar(20)"`
+	PreviousEmployeeID *uint     `json:"previous_employee_id" gorm:"index"`
+	PreviousDate       *string   `json:"previous_date" gorm:"type:date"`
+	PreviousShiftID    *uint     `json:"previous_shift_id"`
+	ChangedBy          uint      `json:"changed_by"`
+	Changer            Employee  `json:"-" gorm:"foreignKey:ChangedBy"`
+	CreatedAt          time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// WeekStart returns the Monday of the week of date
+func WeekStart(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day()-(int(date.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+}
+
+// IsWeekPublished reports whether the week of date (YYYY-MM-DD) is published for the department
+func IsWeekPublished(db *gorm.DB, departmentID int, date string) (bool, error) {
+	day, err := time.Parse("2006-01-02", normalizeDate(date))
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	err = db.Model(&SchedulePublication{}).
+		Where("department_id = ? AND week_start = ?", departmentID, WeekStart(day).Format("2006-01-02")).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// NewScheduleChange describes the current state of a schedule for the change log
+func NewScheduleChange(schedule Schedule, departmentID int, action string, changedBy uint) ScheduleChange {
+	return ScheduleChange{
+		ScheduleID:   schedule.ID,
+		DepartmentID: departmentID,
+		EmployeeID:   schedule.EmployeeID,
+		Action:       action,
+		DateSchedule: normalizeDate(schedule.DateSchedule),
+		ShiftID:      schedule.ShiftID,
+		Status:       schedule.Status,
+		ChangedBy:    changedBy,
+	}
+}
+
+// RecordScheduleChange stores a change of a published schedule
+func RecordScheduleChange(db *gorm.DB, change ScheduleChange) error {
+	return db.Create(&change).Error
+}
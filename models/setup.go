@@ -3,56 +3,69 @@ package models
 import (
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
-	// setup for local
-	dsn := "root:@tcp(127.0.0.1:3306)/hotelqu_db?parseTime=true"
-	// rawURL := os.Getenv("mysql_url")
-	// log.Printf("DEBUG: MYSQL_URL value from environment: %s", rawURL)
-
-	// if rawURL == "" {
-	// 	log.Println("ERROR: MYSQL_URL environment variable is empty!")
-	// 	panic("MYSQL_URL is not set. Please set it in Railway.")
-	// }
-
-	// // Parse MYSQL_URL from railway
-	// parsedURL, err := url.Parse(rawURL)
-	// if err != nil {
-	// 	panic(fmt.Sprintf("Invalid MYSQL_URL format: %v", err))
-	// }
-
-	// user := parsedURL.User.Username()
-	// pass, _ := parsedURL.User.Password()
-	// host := parsedURL.Hostname()
-	// port := parsedURL.Port()
-	// if port == "" {
-	// 	port = "3306"
-	// }
-	// dbName := strings.TrimPrefix(parsedURL.Path, "/")
-
-	// // Create DSN in Go-compatible format
-	// dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", user, pass, host, port, dbName)
-
-	// log.Printf("DEBUG: Final DSN used to connect: %s", dsn)
-
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// openDialector returns the gorm dialector for the selected driver
+func openDialector(driver, dsn string) (gorm.Dialector, error) {
+	switch strings.ToLower(driver) {
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres", "postgresql":
+		return postgres.Open(dsn), nil
+	case "sqlite", "sqlite3":
+		return sqlite.Open(dsn), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q (use mysql, postgres or sqlite)", driver)
+}
+
+func ConnectDatabase(cfg config.DatabaseConfig) {
+	driver, dsn := cfg.Driver, cfg.DSN
+
+	dialector, err := openDialector(driver, dsn)
 	if err != nil {
-		log.Printf("ERROR: Failed to connect to database using DSN: %s", dsn)
+		panic(err.Error())
+	}
+
+	database, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		// the DSN holds the database password, it is not logged
+		log.Printf("ERROR: Failed to connect to %s database", driver)
 		panic(fmt.Sprintf("failed to connect database: %v", err))
 	}
 
 	fmt.Println("Starting database migration...")
-	err = database.AutoMigrate(&Department{}, &Position{}, &Shift{}, &Employee{}, &Schedule{}, &Attendance{}, &Task{}, &TaskItem{})
+	if err := migrateKioskCodeUses(database); err != nil {
+		panic("failed to migrate kiosk code uses: " + err.Error())
+	}
+	if err := migrateDuplicateAttendance(database); err != nil {
+		panic("failed to remove duplicate attendance: " + err.Error())
+	}
+	err = database.AutoMigrate(&Permission{}, &Role{}, &Department{}, &Position{}, &Shift{}, &Employee{}, &Schedule{}, &Attendance{}, &Task{}, &TaskItem{}, &RefreshToken{}, &RevokedToken{}, &PasswordResetToken{}, &LoginThrottle{}, &AuditLog{}, &RosterTemplate{}, &RosterTemplateSlot{}, &ShiftSwapRequest{}, &ShiftSwapEvent{}, &LeaveType{}, &LeaveBalance{}, &LeaveRequest{}, &HolidayCalendar{}, &Holiday{}, &StaffingRule{}, &SchedulePublication{}, &ScheduleChange{}, &CalendarFeedToken{}, &WorkLocation{}, &KioskDevice{}, &KioskCodeUse{})
 	if err != nil {
 		panic("failed to migrate: " + err.Error())
 	}
 
+	if err := migrateLegacyClockTimes(database); err != nil {
+		panic("failed to migrate attendance clock times: " + err.Error())
+	}
+
+	if err := seedRoles(database); err != nil {
+		panic("failed to seed roles: " + err.Error())
+	}
+
+	if err := seedLeaveTypes(database); err != nil {
+		panic("failed to seed leave types: " + err.Error())
+	}
+
 	fmt.Println("Database migration completed successfully")
 	DB = database
 }
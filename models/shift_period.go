@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ParseShiftClock reads a shift time written as HH:MM or HH:MM:SS as the time since midnight
+func ParseShiftClock(value string) (time.Duration, error) {
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid shift time %q", value)
+}
+
+// Period returns when the shift starts and ends when it is worked on date, in the location of date.
+// A shift that ends before it starts ends the next day.
+func (s Shift) Period(date time.Time) (time.Time, time.Time, error) {
+	start, err := ParseShiftClock(s.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := ParseShiftClock(s.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	return day.Add(start), day.Add(end), nil
+}
+
+// FindOverlappingSchedule returns a schedule of the employee whose shift overlaps the shift worked on date,
+// nil when there is none. Schedules listed in ignore are left out.
+func FindOverlappingSchedule(db *gorm.DB, employeeID uint, date time.Time, shift Shift, ignore ...uint) (*Schedule, error) {
+	start, end, err := shift.Period(date)
+	if err != nil {
+		return nil, err
+	}
+
+	// an overnight shift of the day before can still be running
+	query := db.Preload("Shift").Where("employee_id = ? AND date_schedule BETWEEN ? AND ?", employeeID,
+		date.AddDate(0, 0, -1).Format("2006-01-02"), date.AddDate(0, 0, 1).Format("2006-01-02"))
+	if len(ignore) > 0 {
+		query = query.Where("id NOT IN ?", ignore)
+	}
+	var schedules []Schedule
+	if err := query.Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	for i, schedule := range schedules {
+		scheduleDate, err := time.Parse("2006-01-02", normalizeDate(schedule.DateSchedule))
+		if err != nil {
+			continue
+		}
+		otherStart, otherEnd, err := schedule.Shift.Period(scheduleDate)
+		if err != nil {
+			return nil, err
+		}
+		if start.Before(otherEnd) && otherStart.Before(end) {
+			return &schedules[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// shift swap request types
+const (
+	// SwapTypeSwap exchanges the schedules of the requester and a colleague
+	SwapTypeSwap = "swap"
+	// SwapTypeCover hands the requester's schedule to a colleague
+	SwapTypeCover = "cover"
+)
+
+// shift swap request states
+const (
+	SwapStatusPending   = "pending"
+	SwapStatusAccepted  = "accepted"
+	SwapStatusApproved  = "approved"
+	SwapStatusRejected  = "rejected"
+	SwapStatusCancelled = "cancelled"
+	SwapStatusExpired   = "expired"
+)
+
+// swapTransitions lists the states a request may move to from each state
+var swapTransitions = map[string][]string{
+	SwapStatusPending:  {SwapStatusAccepted, SwapStatusRejected, SwapStatusCancelled, SwapStatusExpired},
+	SwapStatusAccepted: {SwapStatusApproved, SwapStatusRejected, SwapStatusCancelled, SwapStatusExpired},
+}
+
+// ShiftSwapRequest asks to swap a schedule with a colleague or to have it covered.
+// A cover request without a target is open to every colleague in the department.
+type ShiftSwapRequest struct {
+	ID                  uint     `json:"id" gorm:"primaryKey"`
+	Type                string   `json:"type" gorm:"type:varchar(10);not null"`
+	Status              string   `json:"status" gorm:"type:varchar(20);not null;index"`
+	DepartmentID        int      `json:"department_id" gorm:"index"`
+	RequesterID         uint     `json:"requester_id" gorm:"index"`
+	Requester           Employee `json:"-" gorm:"foreignKey:RequesterID"`
+	RequesterScheduleID uint     `json:"requester_schedule_id" gorm:"index"`
+	RequesterSchedule   Schedule `json:"-" gorm:"foreignKey:RequesterScheduleID"`
+	TargetEmployeeID    *uint    `json:"target_employee_id" gorm:"index"`
+	TargetEmployee      Employee `json:"-" gorm:"foreignKey:TargetEmployeeID"`
+	TargetScheduleID    *uint    `json:"target_schedule_id" gorm:"index"`
+	TargetSchedule      Schedule `json:"-" gorm:"foreignKey:TargetScheduleID"`
+	Reason              string   `json:"reason" gorm:"type:varchar(255)"`
+	// ExpiresOn (YYYY-MM-DD) is the earliest schedule date, an open request expires after that day
+	ExpiresOn  string           `json:"expires_on" gorm:"type:date;index"`
+	ApprovedBy *uint            `json:"approved_by"`
+	History    []ShiftSwapEvent `json:"history" gorm:"foreignKey:RequestID"`
+	CreatedAt  time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// ShiftSwapEvent records a state transition of a request
+type ShiftSwapEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	RequestID  uint      `json:"request_id" gorm:"index"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	ActorID    *uint     `json:"actor_id"`
+	Note       string    `json:"note" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// CanTransitionTo reports whether the request may move from its current state to the given one
+func (r ShiftSwapRequest) CanTransitionTo(status string) bool {
+	for _, allowed := range swapTransitions[r.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
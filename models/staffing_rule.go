@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// StaffingRule is the headcount a department needs on a shift, for one position or for every
+// position when PositionID is nil. A rule with a Weekday replaces the everyday rule of the same
+// position and shift on that weekday.
+type StaffingRule struct {
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	DepartmentID int      `json:"department_id" gorm:"index"`
+	PositionID   *int     `json:"position_id" gorm:"index"`
+	Position     Position `json:"-" gorm:"foreignKey:PositionID"`
+	ShiftID      uint     `json:"shift_id" gorm:"index"`
+	Shift        Shift    `json:"-" gorm:"foreignKey:ShiftID"`
+	// Weekday follows time.Weekday, 0 is Sunday, nil applies to every day
+	Weekday  *int `json:"weekday"`
+	MinStaff int  `json:"min_staff"`
+	// MaxStaff nil means no upper limit
+	MaxStaff  *int      `json:"max_staff"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// AppliesOn reports whether the rule applies on the weekday
+func (r StaffingRule) AppliesOn(weekday time.Weekday) bool {
+	return r.Weekday == nil || *r.Weekday == int(weekday)
+}
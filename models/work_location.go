@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// geofence policies of a department, off does not check where employees clock in and out
+const (
+	GeofencePolicyOff    = "off"
+	GeofencePolicyFlag   = "flag"
+	GeofencePolicyReject = "reject"
+)
+
+// geofence results stored on attendance, empty when the department does not check locations
+const (
+	GeofenceInside  = "inside"
+	GeofenceOutside = "outside"
+	// GeofenceUnknown means the position was missing or less accurate than allowed
+	GeofenceUnknown = "unknown"
+)
+
+// WorkLocation is a property or site employees clock in at, a punch within RadiusMeters of it is inside the geofence
+type WorkLocation struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null"`
+	Address      string    `json:"address" gorm:"type:varchar(255)"`
+	Latitude     float64   `json:"latitude" gorm:"not null"`
+	Longitude    float64   `json:"longitude" gorm:"not null"`
+	RadiusMeters float64   `json:"radius_meters" gorm:"not null"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
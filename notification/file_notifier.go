@@ -0,0 +1,30 @@
+package notification
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileNotifier appends messages to a file, an outbox that can be read or picked up by another process
+type FileNotifier struct {
+	Path string
+}
+
+var fileMutex sync.Mutex
+
+func (n FileNotifier) Send(message Message) error {
+	fileMutex.Lock()
+	defer fileMutex.Unlock()
+
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "--- %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), message.To, message.Subject, message.Body)
+	return err
+}
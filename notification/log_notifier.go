@@ -0,0 +1,11 @@
+package notification
+
+import "log"
+
+// LogNotifier writes messages to the application log, meant for local development
+type LogNotifier struct{}
+
+func (LogNotifier) Send(message Message) error {
+	log.Printf("NOTIFICATION to=%s subject=%q\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
@@ -0,0 +1,36 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+)
+
+// Message is a notification addressed to one employee
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to employees (password reset codes, etc.)
+type Notifier interface {
+	Send(message Message) error
+}
+
+// Default is the notifier used by the controllers, set by Setup
+var Default Notifier = LogNotifier{}
+
+// Setup selects the notifier from config (notifier.driver: log or file)
+func Setup(cfg config.NotifierConfig) error {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "log":
+		Default = LogNotifier{}
+	case "file":
+		Default = FileNotifier{Path: cfg.FilePath}
+	default:
+		return fmt.Errorf("unsupported notifier driver %q (use log or file)", cfg.Driver)
+	}
+	return nil
+}
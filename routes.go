@@ -0,0 +1,244 @@
+package main
+
+import (
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/attendance"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/audit"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/authentication"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/calendar"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/department"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/employee"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/employment"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/holiday"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/kiosk"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/leave"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/position"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/registration"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/role"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/schedule"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/shift"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/shiftswap"
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/task" // Tambahkan import untuk task
+	"github.com/OrryFrasetyo/go-api-hotelqu/controllers/worklocation"
+	"github.com/OrryFrasetyo/go-api-hotelqu/middlewares"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/gin-gonic/gin"
+)
+
+// setupRouter registers every route of the API
+func setupRouter(cfg *config.Config) *gin.Engine {
+	router := gin.Default()
+
+	// Uploaded photos are only served to logged-in employees, attendance selfies only to their owner
+	// and reviewers of the department
+	uploads := router.Group("/uploads")
+	uploads.Use(middlewares.JWTAuth(), middlewares.RequirePasswordChanged())
+	uploads.GET("/*path", attendance.ServeUpload)
+
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"message": "Hello Hotelqu",
+		})
+	})
+
+	// Auth routes
+	router.POST("/api/register", authentication.Register)
+	router.POST("/api/login", authentication.Login)
+	router.POST("/api/refresh", authentication.Refresh)
+	router.POST("/api/password/forgot", authentication.ForgotPassword)
+	router.POST("/api/password/reset", authentication.ResetPassword)
+
+	// Calendar feeds, calendar apps cannot send a bearer token so the feed token is part of the URL
+	router.GET("/api/calendar/:token/schedule.ics", calendar.EmployeeScheduleFeed)
+	router.GET("/api/calendar/:token/department.ics", calendar.DepartmentScheduleFeed)
+
+	// Kiosk terminals authenticate with their device token (X-Kiosk-Token) instead of an employee login
+	router.GET("/api/kiosk/code", kiosk.GetKioskCode)
+
+	// Session routes, still reachable while a password change is required
+	session := router.Group("/api")
+	session.Use(middlewares.JWTAuth())
+	{
+		session.POST("/logout", authentication.Logout)
+		session.POST("/logout/all", authentication.LogoutAll)
+		session.PUT("/password", authentication.ChangePassword)
+	}
+
+	// Protected routes (requiring JWT authentication)
+	protected := router.Group("/api")
+	protected.Use(middlewares.JWTAuth(), middlewares.RequirePasswordChanged())
+	{
+		// User profile route
+		protected.GET("/user", employee.GetProfile)
+		protected.PUT("/user", employee.UpdateProfile)
+		protected.GET("/employees", employee.GetEmployeesForDepartment)
+
+		// schedules endpoints
+		protected.GET("/schedules", schedule.ListSchedules)
+		protected.GET("/schedules/today", schedule.GetTodaySchedule)
+		protected.GET("/schedules/department", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.ListDepartmentSchedules)
+		protected.GET("/schedules/coverage", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.CoverageReport)
+		protected.GET("/schedules/changes", schedule.ListMyScheduleChanges)
+		protected.GET("/schedules/department/changes", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.ListDepartmentScheduleChanges)
+		protected.GET("/schedules/publications", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.ListSchedulePublications)
+		protected.POST("/schedules/publish", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.PublishSchedules)
+		protected.GET("/calendar-feed", calendar.GetCalendarFeed)
+		protected.POST("/calendar-feed", calendar.CreateCalendarFeed)
+		protected.DELETE("/calendar-feed", calendar.RevokeCalendarFeed)
+		protected.POST("/schedules", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.CreateSchedule)
+		protected.POST("/schedules/bulk", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.BulkCreateSchedules)
+		protected.PUT("/schedules/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.UpdateSchedule)
+		protected.DELETE("/schedules/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.DeleteSchedule)
+
+		// staffing rules of the caller's department
+		protected.GET("/staffing-rules", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.ListStaffingRules)
+		protected.POST("/staffing-rules", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.CreateStaffingRule)
+		protected.PUT("/staffing-rules/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.UpdateStaffingRule)
+		protected.DELETE("/staffing-rules/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.DeleteStaffingRule)
+
+		// roster templates of the caller's department
+		protected.GET("/roster-templates", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.ListRosterTemplates)
+		protected.GET("/roster-templates/:id", middlewares.RequirePermission(models.PermissionScheduleRead), schedule.GetRosterTemplate)
+		protected.POST("/roster-templates", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.CreateRosterTemplate)
+		protected.PUT("/roster-templates/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.UpdateRosterTemplate)
+		protected.DELETE("/roster-templates/:id", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.DeleteRosterTemplate)
+		protected.POST("/roster-templates/:id/apply", middlewares.RequirePermission(models.PermissionScheduleWrite), schedule.ApplyRosterTemplate)
+
+		// shift swap and cover requests
+		protected.POST("/shift-swaps", shiftswap.CreateShiftSwap)
+		protected.GET("/shift-swaps", shiftswap.ListMyShiftSwaps)
+		protected.GET("/shift-swaps/department", middlewares.RequirePermission(models.PermissionScheduleRead), shiftswap.ListDepartmentShiftSwaps)
+		protected.GET("/shift-swaps/:id", shiftswap.GetShiftSwap)
+		protected.PUT("/shift-swaps/:id/accept", shiftswap.AcceptShiftSwap)
+		protected.PUT("/shift-swaps/:id/decline", shiftswap.DeclineShiftSwap)
+		protected.PUT("/shift-swaps/:id/cancel", shiftswap.CancelShiftSwap)
+		protected.PUT("/shift-swaps/:id/approve", middlewares.RequirePermission(models.PermissionScheduleWrite), shiftswap.ApproveShiftSwap)
+		protected.PUT("/shift-swaps/:id/reject", middlewares.RequirePermission(models.PermissionScheduleWrite), shiftswap.RejectShiftSwap)
+
+		// leave types (write requires master_data:write), balances and requests
+		protected.GET("/leave-types", leave.ListLeaveTypes)
+		protected.POST("/leave-types", middlewares.RequirePermission(models.PermissionMasterDataWrite), leave.CreateLeaveType)
+		protected.PUT("/leave-types/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), leave.UpdateLeaveType)
+		protected.GET("/leave-balances", leave.GetMyLeaveBalances)
+		protected.POST("/leave-requests", leave.CreateLeaveRequest)
+		protected.GET("/leave-requests", leave.ListMyLeaveRequests)
+		protected.PUT("/leave-requests/:id/cancel", leave.CancelLeaveRequest)
+		protected.GET("/leave-requests/department", middlewares.RequirePermission(models.PermissionLeaveApprove), leave.ListDepartmentLeaveRequests)
+		protected.PUT("/leave-requests/:id/approve", middlewares.RequirePermission(models.PermissionLeaveApprove), leave.ApproveLeaveRequest)
+		protected.PUT("/leave-requests/:id/reject", middlewares.RequirePermission(models.PermissionLeaveApprove), leave.RejectLeaveRequest)
+
+		// attendance endpoints
+		protected.POST("/attendance", attendance.CreateAttendance)
+		protected.PUT("/attendance", attendance.UpdateAttendance)
+		protected.GET("/attendance", attendance.GetAttendanceLastThreeDays)
+		protected.GET("/attendance/today", attendance.GetAttendanceToday)
+		protected.GET("/attendance/month", attendance.GetAttendanceThisMonth)
+		protected.GET("/attendance/status", attendance.GetAttendanceByStatus)
+		protected.GET("/attendance/department", middlewares.RequirePermission(models.PermissionAttendanceReview), attendance.ListDepartmentAttendance)
+		protected.PUT("/attendance/:id/photo-review", middlewares.RequirePermission(models.PermissionAttendanceReview), attendance.ReviewAttendancePhoto)
+
+		// Task route for employees (accessible by all authenticated users)
+		protected.GET("/task", task.ListTaskEmployee)
+		protected.PUT("/task/:id", task.ChecklistTask)
+
+		// task endpoints (hanya untuk role dengan permission task:write)
+		taskRoutes := protected.Group("/tasks")
+		taskRoutes.Use(middlewares.RequirePermission(models.PermissionTaskWrite))
+		{
+			taskRoutes.POST("", task.CreateTask)
+			taskRoutes.GET("/department", task.ListDepartmentTasks)
+			taskRoutes.PUT("/:id", task.UpdateTask)
+			taskRoutes.PUT("/status/:id", task.UpdateTaskStatus)
+			taskRoutes.DELETE("/:id", task.DeleteTask)
+			taskRoutes.PUT("/restore/:id", task.RestoreTask)
+		}
+
+		// role and permission management
+		protected.GET("/permissions", middlewares.RequirePermission(models.PermissionRoleManage), role.FindPermissions)
+		roleRoutes := protected.Group("/roles")
+		roleRoutes.Use(middlewares.RequirePermission(models.PermissionRoleManage))
+		{
+			roleRoutes.GET("", role.FindRoles)
+			roleRoutes.POST("", role.StoreRole)
+			roleRoutes.PUT("/:id", role.UpdateRole)
+		}
+
+		// admin endpoints for managing employee accounts
+		adminRoutes := protected.Group("/admin")
+		adminRoutes.Use(middlewares.RequirePermission(models.PermissionEmployeeManage))
+		{
+			adminRoutes.GET("/employees", employee.FindEmployees)
+			adminRoutes.GET("/employees/:id", employee.FindEmployeeById)
+			adminRoutes.PUT("/employees/:id", employee.UpdateEmployee)
+			adminRoutes.POST("/employees/:id/logout-all", authentication.RevokeEmployeeSessions)
+			adminRoutes.PUT("/employees/:id/force-password-change", authentication.ForcePasswordChange)
+			adminRoutes.PUT("/employees/:id/unlock", authentication.UnlockEmployeeLogin)
+			adminRoutes.PUT("/employees/:id/deactivate", employment.DeactivateEmployee)
+			adminRoutes.PUT("/employees/:id/terminate", employment.TerminateEmployee)
+			adminRoutes.PUT("/employees/:id/reactivate", employment.ReactivateEmployee)
+			adminRoutes.GET("/employees/:id/leave-balances", leave.GetEmployeeLeaveBalances)
+			adminRoutes.PUT("/employees/:id/leave-balances", leave.SetEmployeeLeaveBalance)
+			adminRoutes.GET("/login-locks", authentication.FindLoginLocks)
+			adminRoutes.DELETE("/login-locks/:id", authentication.UnlockLogin)
+			adminRoutes.GET("/audit-logs", audit.FindAuditLogs)
+
+			adminRoutes.GET("/registrations", registration.FindRegistrations)
+			adminRoutes.PUT("/registrations/:id/approve", registration.ApproveRegistration)
+			adminRoutes.PUT("/registrations/:id/reject", registration.RejectRegistration)
+		}
+
+		// Department routes (read for every employee, write requires master_data:write)
+		protected.GET("/departments", department.FindDepartments)
+		protected.GET("/departments/:id", department.FindDepartmentById)
+		protected.POST("/departments", middlewares.RequirePermission(models.PermissionMasterDataWrite), department.StoreDepartment)
+		protected.PUT("/departments/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), department.UpdateDepartment)
+		protected.DELETE("/departments/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), department.DeleteDepartment)
+
+		// Position routes (read for every employee, write requires master_data:write)
+		protected.GET("/positions", position.FindPositions)
+		protected.GET("/positions/:id", position.FindPositionById)
+		protected.POST("/positions", middlewares.RequirePermission(models.PermissionMasterDataWrite), position.StorePosition)
+		protected.PUT("/positions/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), position.UpdatePosition)
+		protected.DELETE("/positions/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), position.DeletePosition)
+
+		// Shift routes (read for every employee, write requires master_data:write)
+		protected.GET("/shifts", shift.FindShifts)
+		protected.GET("/shifts/:id", shift.FindShiftById)
+		protected.POST("/shifts", middlewares.RequirePermission(models.PermissionMasterDataWrite), shift.StoreShift)
+		protected.PUT("/shifts/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), shift.UpdateShift)
+		protected.DELETE("/shifts/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), shift.DeleteShift)
+
+		// Holiday calendars (read for every employee, write requires master_data:write)
+		protected.GET("/holidays", holiday.ListHolidays)
+		protected.GET("/holiday-calendars", holiday.ListHolidayCalendars)
+		protected.GET("/holiday-calendars/:id", holiday.GetHolidayCalendar)
+		protected.POST("/holiday-calendars", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.CreateHolidayCalendar)
+		protected.PUT("/holiday-calendars/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.UpdateHolidayCalendar)
+		protected.DELETE("/holiday-calendars/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.DeleteHolidayCalendar)
+		protected.POST("/holiday-calendars/:id/import", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.ImportHolidays)
+		protected.POST("/holiday-calendars/:id/holidays", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.CreateHoliday)
+		protected.PUT("/holiday-calendars/:id/holidays/:holidayId", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.UpdateHoliday)
+		protected.DELETE("/holiday-calendars/:id/holidays/:holidayId", middlewares.RequirePermission(models.PermissionMasterDataWrite), holiday.DeleteHoliday)
+
+		// Work locations used for the attendance geofence (read for every employee, write requires master_data:write)
+		protected.GET("/work-locations", worklocation.ListWorkLocations)
+		protected.GET("/work-locations/:id", worklocation.GetWorkLocation)
+		protected.POST("/work-locations", middlewares.RequirePermission(models.PermissionMasterDataWrite), worklocation.CreateWorkLocation)
+		protected.PUT("/work-locations/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), worklocation.UpdateWorkLocation)
+		protected.DELETE("/work-locations/:id", middlewares.RequirePermission(models.PermissionMasterDataWrite), worklocation.DeleteWorkLocation)
+
+		// Kiosk registration (requires master_data:write)
+		kioskRoutes := protected.Group("/kiosks")
+		kioskRoutes.Use(middlewares.RequirePermission(models.PermissionMasterDataWrite))
+		{
+			kioskRoutes.GET("", kiosk.ListKiosks)
+			kioskRoutes.POST("", kiosk.CreateKiosk)
+			kioskRoutes.PUT("/:id", kiosk.UpdateKiosk)
+			kioskRoutes.DELETE("/:id", kiosk.DeleteKiosk)
+			kioskRoutes.POST("/:id/rotate-secret", kiosk.RotateKioskSecret)
+			kioskRoutes.POST("/:id/token", kiosk.ReissueKioskToken)
+		}
+	}
+
+	return router
+}
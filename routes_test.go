@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"github.com/OrryFrasetyo/go-api-hotelqu/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// setupTestRouter connects a fresh SQLite database and returns the API router
+func setupTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	t.Setenv("UPLOAD_DIR", t.TempDir())
+	gin.SetMode(gin.TestMode)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	models.ConnectDatabase(cfg.Database)
+	return setupRouter(cfg)
+}
+
+// createTestEmployee creates an active employee in a position with the named role and returns an access token
+func createTestEmployee(t *testing.T, email, roleName string) string {
+	t.Helper()
+	role, err := models.FindRoleByName(roleName)
+	if err != nil {
+		t.Fatalf("role %s: %v", roleName, err)
+	}
+	department := models.Department{DepartmentName: "Front Office"}
+	if err := models.DB.Create(&department).Error; err != nil {
+		t.Fatalf("create department: %v", err)
+	}
+	position := models.Position{DepartmentId: department.Id, RoleId: &role.Id, PositionName: roleName}
+	if err := models.DB.Create(&position).Error; err != nil {
+		t.Fatalf("create position: %v", err)
+	}
+	employee := models.Employee{PositionId: position.Id, Name: email, Email: email, Password: "-", Phone: "1",
+		Status: models.EmployeeStatusActive}
+	if err := models.DB.Create(&employee).Error; err != nil {
+		t.Fatalf("create employee: %v", err)
+	}
+	token, _, err := utils.GenerateToken(employee)
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	return token
+}
+
+func doRequest(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMasterDataReads(t *testing.T) {
+	router := setupTestRouter(t)
+	token := createTestEmployee(t, "staff@example.com", models.RoleEmployee)
+	shift := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	models.DB.Create(&shift)
+
+	paths := []string{"/api/departments", "/api/departments/1", "/api/positions", "/api/positions/1",
+		"/api/shifts", fmt.Sprintf("/api/shifts/%d", shift.ID)}
+	for _, path := range paths {
+		if w := doRequest(router, http.MethodGet, path, "", ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s without token: got %d, want 401", path, w.Code)
+		}
+		if w := doRequest(router, http.MethodGet, path, token, ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s as employee: got %d, want 200 (%s)", path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestMasterDataWrites(t *testing.T) {
+	router := setupTestRouter(t)
+	staff := createTestEmployee(t, "staff@example.com", models.RoleEmployee)
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin)
+
+	writes := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/departments", `{"department_name":"Housekeeping"}`},
+		{http.MethodPut, "/api/departments/1", `{"department_name":"Front Desk"}`},
+		{http.MethodPost, "/api/positions", `{"department_id":1,"position_name":"Receptionist"}`},
+		{http.MethodPut, "/api/positions/1", `{"department_id":1,"position_name":"Night Auditor"}`},
+		{http.MethodPost, "/api/shifts", `{"type":"Night","start_time":"23:00","end_time":"07:00"}`},
+		{http.MethodPut, "/api/shifts/1", `{"type":"Late Night","start_time":"23:00","end_time":"07:00"}`},
+		{http.MethodDelete, "/api/shifts/1", ""},
+	}
+	for _, write := range writes {
+		if w := doRequest(router, write.method, write.path, staff, write.body); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s as employee: got %d, want 403", write.method, write.path, w.Code)
+		}
+		if w := doRequest(router, write.method, write.path, admin, write.body); w.Code < 200 || w.Code > 299 {
+			t.Errorf("%s %s with master_data:write: got %d, want 2xx (%s)", write.method, write.path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestPositionRoleEscalation(t *testing.T) {
+	router := setupTestRouter(t)
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin)
+	super := createTestEmployee(t, "gm@example.com", models.RoleSuperAdmin)
+	superAdmin, _ := models.FindRoleByName(models.RoleSuperAdmin)
+	employee, _ := models.FindRoleByName(models.RoleEmployee)
+
+	grant := fmt.Sprintf(`{"department_id":1,"position_name":"HR","role_id":%d}`, superAdmin.Id)
+	if w := doRequest(router, http.MethodPost, "/api/positions", admin, grant); w.Code != http.StatusForbidden {
+		t.Errorf("create position with super_admin role as hr_admin: got %d, want 403", w.Code)
+	}
+	// position 1 is the hr_admin's own
+	if w := doRequest(router, http.MethodPut, "/api/positions/1", admin, grant); w.Code != http.StatusForbidden {
+		t.Errorf("give own position the super_admin role as hr_admin: got %d, want 403", w.Code)
+	}
+	// position 2 belongs to the super admin
+	demote := fmt.Sprintf(`{"department_id":2,"position_name":"GM","role_id":%d}`, employee.Id)
+	if w := doRequest(router, http.MethodPut, "/api/positions/2", admin, demote); w.Code != http.StatusForbidden {
+		t.Errorf("take the super_admin role away as hr_admin: got %d, want 403", w.Code)
+	}
+	lesser := fmt.Sprintf(`{"department_id":1,"position_name":"Clerk","role_id":%d}`, employee.Id)
+	if w := doRequest(router, http.MethodPost, "/api/positions", admin, lesser); w.Code != http.StatusCreated {
+		t.Errorf("create position with employee role as hr_admin: got %d, want 201 (%s)", w.Code, w.Body)
+	}
+	if w := doRequest(router, http.MethodPut, "/api/positions/1", super, grant); w.Code != http.StatusOK {
+		t.Errorf("give a position the super_admin role with role:manage: got %d, want 200 (%s)", w.Code, w.Body)
+	}
+}
+
+func TestEmployeePositionEscalation(t *testing.T) {
+	router := setupTestRouter(t)
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)         // employee 1, position 1
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin)    // employee 2, position 2
+	super := createTestEmployee(t, "gm@example.com", models.RoleSuperAdmin) // employee 3, position 3
+	createTestEmployee(t, "clerk@example.com", models.RoleEmployee)         // employee 4, position 4
+
+	cases := []struct {
+		name, token, path, body string
+		want                    int
+	}{
+		{"hr_admin moves themselves", admin, "/api/admin/employees/2", `{"position_id":1}`, http.StatusForbidden},
+		{"hr_admin promotes to super_admin", admin, "/api/admin/employees/1", `{"position_id":3}`, http.StatusForbidden},
+		{"hr_admin demotes the super admin", admin, "/api/admin/employees/3", `{"position_id":1}`, http.StatusForbidden},
+		{"hr_admin moves between employee positions", admin, "/api/admin/employees/1", `{"position_id":4}`, http.StatusOK},
+		{"super admin promotes", super, "/api/admin/employees/1", `{"position_id":2}`, http.StatusOK},
+	}
+	for _, tc := range cases {
+		if w := doRequest(router, http.MethodPut, tc.path, tc.token, tc.body); w.Code != tc.want {
+			t.Errorf("%s: got %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body)
+		}
+	}
+}
+
+func TestRegistrationApprovalEscalation(t *testing.T) {
+	router := setupTestRouter(t)
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin)    // position 1
+	super := createTestEmployee(t, "gm@example.com", models.RoleSuperAdmin) // position 2
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)         // position 3
+
+	register := func(email string, positionId int) string {
+		pending := models.Employee{PositionId: positionId, Name: email, Email: email, Password: "-", Phone: "1",
+			Status: models.EmployeeStatusPending}
+		if err := models.DB.Create(&pending).Error; err != nil {
+			t.Fatalf("create registration: %v", err)
+		}
+		return fmt.Sprintf("/api/admin/registrations/%d/approve", pending.Id)
+	}
+
+	cases := []struct {
+		name, token, path, body string
+		want                    int
+	}{
+		{"hr_admin approves into the super_admin position", admin, register("a@example.com", 2), "", http.StatusForbidden},
+		{"hr_admin moves a registration to the super_admin position", admin, register("b@example.com", 3), `{"position_id":2}`, http.StatusForbidden},
+		{"hr_admin approves into an employee position", admin, register("c@example.com", 3), "", http.StatusOK},
+		{"super admin approves into the super_admin position", super, register("d@example.com", 2), "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		if w := doRequest(router, http.MethodPut, tc.path, tc.token, tc.body); w.Code != tc.want {
+			t.Errorf("%s: got %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body)
+		}
+	}
+}
+
+func TestEmployeeAccountActionsOnHigherRoles(t *testing.T) {
+	router := setupTestRouter(t)
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin) // employee 1
+	createTestEmployee(t, "gm@example.com", models.RoleSuperAdmin)       // employee 2
+
+	actions := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/admin/employees/2/logout-all", ""},
+		{http.MethodPut, "/api/admin/employees/2/force-password-change", ""},
+		{http.MethodPut, "/api/admin/employees/2/deactivate", `{"reason":"test"}`},
+		{http.MethodPut, "/api/admin/employees/2/terminate", `{"effective_date":"31-12-2026","reason":"test"}`},
+	}
+	for _, action := range actions {
+		if w := doRequest(router, action.method, action.path, admin, action.body); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s on a super admin as hr_admin: got %d, want 403 (%s)", action.method, action.path, w.Code, w.Body)
+		}
+	}
+}
+
+func TestEmployeeStatusChangeRemovesSchedules(t *testing.T) {
+	router := setupTestRouter(t)
+	admin := createTestEmployee(t, "hr@example.com", models.RoleHRAdmin) // employee 1
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)      // employee 2
+	createTestEmployee(t, "clerk@example.com", models.RoleEmployee)      // employee 3
+	shift := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	models.DB.Create(&shift)
+	for _, employeeID := range []uint{2, 3} {
+		for _, date := range []string{"2099-01-01", "2099-01-02", "2099-01-03"} {
+			models.DB.Create(&models.Schedule{EmployeeID: employeeID, ShiftID: shift.ID, CreatedBy: 1, DateSchedule: date})
+		}
+	}
+	remaining := func(employeeID uint) int64 {
+		var count int64
+		models.DB.Model(&models.Schedule{}).Where("employee_id = ?", employeeID).Count(&count)
+		return count
+	}
+
+	if w := doRequest(router, http.MethodPut, "/api/admin/employees/2/terminate", admin,
+		`{"effective_date":"01-01-2099","reason":"contract ended"}`); w.Code != http.StatusOK {
+		t.Fatalf("terminate: got %d, want 200 (%s)", w.Code, w.Body)
+	}
+	if count := remaining(2); count != 1 {
+		t.Errorf("schedules after termination: %d left, want only the last working day", count)
+	}
+	if w := doRequest(router, http.MethodPut, "/api/admin/employees/3/deactivate", admin, `{"reason":"left"}`); w.Code != http.StatusOK {
+		t.Fatalf("deactivate: got %d, want 200 (%s)", w.Code, w.Body)
+	}
+	if count := remaining(3); count != 0 {
+		t.Errorf("schedules after deactivation: %d left, want 0", count)
+	}
+}
+
+func TestUploadsNeedOwnerOrReviewer(t *testing.T) {
+	router := setupTestRouter(t)
+	staff := createTestEmployee(t, "staff@example.com", models.RoleEmployee)         // employee 1
+	colleague := createTestEmployee(t, "colleague@example.com", models.RoleEmployee) // employee 2
+	supervisor := createTestEmployee(t, "lead@example.com", models.RoleSupervisor)   // employee 3
+	outsider := createTestEmployee(t, "other@example.com", models.RoleSupervisor)    // employee 4
+	// the colleague and the supervisor work in the department of the staff member
+	models.DB.Model(&models.Position{}).Where("id IN ?", []int{2, 3}).Update("department_id", 1)
+
+	for _, name := range []string{"attendance/1_in_selfie.jpg", "profile/2_photo.jpg"} {
+		file := filepath.Join(config.App.Upload.Dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("create upload directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte("image"), 0644); err != nil {
+			t.Fatalf("write upload: %v", err)
+		}
+	}
+	shift := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	models.DB.Create(&shift)
+	schedule := models.Schedule{EmployeeID: 1, ShiftID: shift.ID, CreatedBy: 3, DateSchedule: "2099-01-01"}
+	models.DB.Create(&schedule)
+	selfie := "/uploads/attendance/1_in_selfie.jpg"
+	models.DB.Create(&models.Attendance{ScheduleID: schedule.ID, Date: "2099-01-01", ClockInPhoto: &selfie})
+
+	cases := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"selfie without a token", selfie, "", http.StatusUnauthorized},
+		{"own selfie", selfie, staff, http.StatusOK},
+		{"selfie of a colleague", selfie, colleague, http.StatusForbidden},
+		{"selfie reviewed in the department", selfie, supervisor, http.StatusOK},
+		{"selfie of another department", selfie, outsider, http.StatusForbidden},
+		{"profile photo", "/uploads/profile/2_photo.jpg", staff, http.StatusOK},
+		{"upload directory", "/uploads/attendance/", staff, http.StatusNotFound},
+		{"missing file", "/uploads/profile/missing.jpg", staff, http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := doRequest(router, http.MethodGet, tc.path, tc.token, ""); w.Code != tc.want {
+				t.Errorf("got %d, want %d (%s)", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestScheduleLaborOverride(t *testing.T) {
+	router := setupTestRouter(t)
+	supervisor := createTestEmployee(t, "lead@example.com", models.RoleSupervisor) // employee 1
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)                // employee 2
+	models.DB.Model(&models.Position{}).Where("id = ?", 2).Update("department_id", 1)
+	evening := models.Shift{Type: "Evening", StartTime: "15:00", EndTime: "23:00"}
+	morning := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	models.DB.Create(&evening)
+	models.DB.Create(&morning)
+	models.DB.Create(&models.Schedule{EmployeeID: 2, ShiftID: evening.ID, CreatedBy: 1, DateSchedule: "2099-03-02"})
+
+	// a morning shift after an evening shift leaves 8 hours of rest
+	request := `{"employee_id":2,"shift_id":%d,"date_schedule":"03-03-2099","status":"hadir"%s}`
+	cases := []struct {
+		name     string
+		override string
+		want     int
+	}{
+		{"without override", "", http.StatusUnprocessableEntity},
+		{"override without a reason", `,"override_labor_rules":true`, http.StatusBadRequest},
+		{"override with a reason", `,"override_labor_rules":true,"override_reason":"covering a sick colleague"`, http.StatusCreated},
+	}
+	for _, tc := range cases {
+		w := doRequest(router, http.MethodPost, "/api/schedules", supervisor, fmt.Sprintf(request, morning.ID, tc.override))
+		if w.Code != tc.want {
+			t.Errorf("%s: got %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body)
+		}
+	}
+
+	var audits []models.AuditLog
+	models.DB.Where("action = ?", models.AuditScheduleLaborOverride).Find(&audits)
+	if len(audits) != 1 || !strings.Contains(audits[0].Detail, models.LaborRuleMinRest) ||
+		!strings.Contains(audits[0].Detail, "covering a sick colleague") {
+		t.Errorf("override audit = %+v, want one entry with the rule and the reason", audits)
+	}
+}
+
+func TestSchedulePublication(t *testing.T) {
+	router := setupTestRouter(t)
+	supervisor := createTestEmployee(t, "lead@example.com", models.RoleSupervisor) // employee 1
+	staff := createTestEmployee(t, "staff@example.com", models.RoleEmployee)       // employee 2
+	models.DB.Model(&models.Position{}).Where("id = ?", 2).Update("department_id", 1)
+	morning := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	evening := models.Shift{Type: "Evening", StartTime: "15:00", EndTime: "23:00"}
+	models.DB.Create(&morning)
+	models.DB.Create(&evening)
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("02-01-2006")
+
+	// count decodes a list from the response of the staff member
+	count := func(path, field string) int {
+		t.Helper()
+		w := doRequest(router, http.MethodGet, path, staff, "")
+		var response map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET %s: got %d (%s)", path, w.Code, w.Body)
+		}
+		return len(response[field].([]interface{}))
+	}
+	update := func(body string) {
+		t.Helper()
+		if w := doRequest(router, http.MethodPut, "/api/schedules/1", supervisor, body); w.Code != http.StatusOK {
+			t.Fatalf("update: got %d (%s)", w.Code, w.Body)
+		}
+	}
+
+	w := doRequest(router, http.MethodPost, "/api/schedules", supervisor,
+		fmt.Sprintf(`{"employee_id":2,"shift_id":%d,"date_schedule":"%s","status":"hadir"}`, morning.ID, tomorrow))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: got %d (%s)", w.Code, w.Body)
+	}
+	update(fmt.Sprintf(`{"shift_id":%d}`, evening.ID))
+	if n := count("/api/schedules", "schedules"); n != 0 {
+		t.Errorf("draft schedules visible to the employee: %d, want 0", n)
+	}
+	if n := count("/api/schedules/changes", "changes"); n != 0 {
+		t.Errorf("changes to a draft: %d, want 0", n)
+	}
+
+	w = doRequest(router, http.MethodPost, "/api/schedules/publish", supervisor, fmt.Sprintf(`{"week_start":"%s"}`, tomorrow))
+	if w.Code != http.StatusOK {
+		t.Fatalf("publish: got %d (%s)", w.Code, w.Body)
+	}
+	if n := count("/api/schedules", "schedules"); n != 1 {
+		t.Errorf("published schedules visible to the employee: %d, want 1", n)
+	}
+
+	update(fmt.Sprintf(`{"shift_id":%d}`, morning.ID))
+	if n := count("/api/schedules/changes", "changes"); n != 1 {
+		t.Errorf("changes after publication: %d, want 1", n)
+	}
+}
+
+func TestBulkCreateSchedules(t *testing.T) {
+	router := setupTestRouter(t)
+	supervisor := createTestEmployee(t, "lead@example.com", models.RoleSupervisor) // employee 1
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)                // employee 2
+	createTestEmployee(t, "clerk@example.com", models.RoleEmployee)                // employee 3
+	models.DB.Model(&models.Position{}).Where("id IN ?", []int{2, 3}).Update("department_id", 1)
+	morning := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	models.DB.Create(&morning)
+	// the Wednesday of employee 2 is already planned
+	models.DB.Create(&models.Schedule{EmployeeID: 2, ShiftID: morning.ID, CreatedBy: 1, DateSchedule: "2099-03-04"})
+
+	// Monday 2 to Sunday 8 March 2099, employee 3 works every other day from Tuesday
+	request := fmt.Sprintf(`{"start_date":"02-03-2099","end_date":"08-03-2099","assignments":[
+		{"employee_id":2,"weekly":{"monday":%[1]d,"wednesday":%[1]d,"friday":%[1]d}},
+		{"employee_id":3,"cycle":[%[1]d,0],"cycle_offset":1}]%%s}`, morning.ID)
+	schedules := func() int64 {
+		var count int64
+		models.DB.Model(&models.Schedule{}).Count(&count)
+		return count
+	}
+
+	cases := []struct {
+		name    string
+		options string
+		want    int
+		summary string
+		total   int64
+	}{
+		{"abort on conflict", `,"on_conflict":"abort"`, http.StatusConflict, `"conflict":1`, 1},
+		{"dry run", `,"dry_run":true`, http.StatusOK, `"ready":5`, 1},
+		{"skip conflicts", "", http.StatusCreated, `"created":5`, 6},
+	}
+	for _, tc := range cases {
+		w := doRequest(router, http.MethodPost, "/api/schedules/bulk", supervisor, fmt.Sprintf(request, tc.options))
+		if w.Code != tc.want || !strings.Contains(w.Body.String(), tc.summary) {
+			t.Errorf("%s: got %d, want %d with %s (%s)", tc.name, w.Code, tc.want, tc.summary, w.Body)
+		}
+		if total := schedules(); total != tc.total {
+			t.Errorf("%s: %d schedules, want %d", tc.name, total, tc.total)
+		}
+	}
+
+	var dates []string
+	models.DB.Model(&models.Schedule{}).Where("employee_id = ?", 3).Order("date_schedule").Pluck("date_schedule", &dates)
+	for i, date := range dates {
+		dates[i] = date[:10]
+	}
+	if fmt.Sprint(dates) != "[2099-03-03 2099-03-05 2099-03-07]" {
+		t.Errorf("cycle dates = %v, want Tuesday, Thursday and Saturday", dates)
+	}
+}
+
+func TestApplyRosterTemplate(t *testing.T) {
+	router := setupTestRouter(t)
+	supervisor := createTestEmployee(t, "lead@example.com", models.RoleSupervisor) // employee 1
+	createTestEmployee(t, "staff@example.com", models.RoleEmployee)                // employee 2, position 2
+	createTestEmployee(t, "clerk@example.com", models.RoleEmployee)                // employee 3, position 3
+	models.DB.Model(&models.Position{}).Where("id IN ?", []int{2, 3}).Update("department_id", 1)
+	// a disabled colleague in position 2 is not planned
+	models.DB.Create(&models.Employee{PositionId: 2, Name: "former", Email: "former@example.com", Password: "-", Phone: "1",
+		Status: models.EmployeeStatusDisabled})
+	morning := models.Shift{Type: "Morning", StartTime: "07:00", EndTime: "15:00"}
+	evening := models.Shift{Type: "Evening", StartTime: "15:00", EndTime: "23:00"}
+	models.DB.Create(&morning)
+	models.DB.Create(&evening)
+
+	// the employee slot of employee 3 on Tuesday wins over the slot of their position
+	w := doRequest(router, http.MethodPost, "/api/roster-templates", supervisor, fmt.Sprintf(`{"name":"Week","slots":[
+		{"weekday":"monday","position_id":2,"shift_id":%[1]d},
+		{"weekday":"tuesday","employee_id":3,"shift_id":%[1]d},
+		{"weekday":"tuesday","position_id":3,"shift_id":%[2]d},
+		{"weekday":"wednesday","position_id":3,"shift_id":%[2]d}]}`, morning.ID, evening.ID))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create template: got %d (%s)", w.Code, w.Body)
+	}
+
+	cases := []struct {
+		name    string
+		want    int
+		summary string
+	}{
+		{"first week", http.StatusCreated, `"created":3`},
+		{"applied again", http.StatusCreated, `"conflict":3`},
+	}
+	for _, tc := range cases {
+		w := doRequest(router, http.MethodPost, "/api/roster-templates/1/apply", supervisor, `{"week_start":"02-03-2099"}`)
+		if w.Code != tc.want || !strings.Contains(w.Body.String(), tc.summary) {
+			t.Errorf("%s: got %d, want %d with %s (%s)", tc.name, w.Code, tc.want, tc.summary, w.Body)
+		}
+	}
+
+	var schedules []models.Schedule
+	models.DB.Order("date_schedule, employee_id").Find(&schedules)
+	var got []string
+	for _, schedule := range schedules {
+		got = append(got, fmt.Sprintf("%d %s %d", schedule.EmployeeID, schedule.DateSchedule[:10], schedule.ShiftID))
+	}
+	want := fmt.Sprintf("[2 2099-03-02 %[1]d 3 2099-03-03 %[1]d 3 2099-03-04 %[2]d]", morning.ID, evening.ID)
+	if fmt.Sprint(got) != want {
+		t.Errorf("schedules = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"gorm.io/gorm"
+)
+
+var ErrCalendarFeedTokenInvalid = errors.New("invalid calendar feed token")
+
+// CreateCalendarFeedToken returns a new feed token for the employee, the previous one stops working
+func CreateCalendarFeedToken(employeeID int) (string, models.CalendarFeedToken, error) {
+	plainToken, err := randomHex(32)
+	if err != nil {
+		return "", models.CalendarFeedToken{}, err
+	}
+
+	feedToken := models.CalendarFeedToken{EmployeeID: employeeID, TokenHash: HashToken(plainToken)}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("employee_id = ?", employeeID).Delete(&models.CalendarFeedToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&feedToken).Error
+	})
+	if err != nil {
+		return "", models.CalendarFeedToken{}, err
+	}
+	return plainToken, feedToken, nil
+}
+
+// FindCalendarFeedToken looks up a feed token with its employee and marks it as used
+func FindCalendarFeedToken(plainToken string) (models.CalendarFeedToken, error) {
+	var feedToken models.CalendarFeedToken
+	if err := models.DB.Preload("Employee.Position.Department").Preload("Employee.Position.Role.Permissions").
+		Where("token_hash = ?", HashToken(plainToken)).First(&feedToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return feedToken, ErrCalendarFeedTokenInvalid
+		}
+		return feedToken, err
+	}
+
+	now := time.Now()
+	models.DB.Model(&feedToken).Update("last_used_at", now)
+	feedToken.LastUsedAt = &now
+	return feedToken, nil
+}
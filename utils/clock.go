@@ -0,0 +1,6 @@
+package utils
+
+import "time"
+
+// Now returns the current server time. Attendance reads the time through it so tests can set the clock.
+var Now = time.Now
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	cases := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want, tolerance        float64
+	}{
+		{"same point", -6.2, 106.8166, -6.2, 106.8166, 0, 0.001},
+		{"one degree of latitude", 0, 0, 1, 0, 111195, 1},
+		{"one degree of longitude at the equator", 0, 0, 0, 1, 111195, 1},
+		{"across the date line", 0, 179.9995, 0, -179.9995, 111.2, 0.1},
+		{"hotel lobby to the parking lot", -6.17539, 106.82715, -6.17589, 106.82755, 70.7, 1},
+		{"Jakarta to Bandung", -6.2088, 106.8456, -6.9175, 107.6191, 116000, 1000},
+		{"opposite sides of the earth", 0, 0, 0, 180, math.Pi * earthRadiusMeters, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DistanceMeters(tc.lat1, tc.lon1, tc.lat2, tc.lon2)
+			if math.Abs(got-tc.want) > tc.tolerance {
+				t.Errorf("distance = %.1f m, want %.1f ± %.1f", got, tc.want, tc.tolerance)
+			}
+			if back := DistanceMeters(tc.lat2, tc.lon2, tc.lat1, tc.lon1); math.Abs(back-got) > 1e-6 {
+				t.Errorf("distance back = %.3f m, want %.3f", back, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// ICalEvent is an all-day event read from an iCalendar file
+type ICalEvent struct {
+	UID     string
+	Summary string
+	// Start is the first day, End the day after the last one
+	Start time.Time
+	End   time.Time
+	// Yearly is set for events repeated every year (RRULE:FREQ=YEARLY)
+	Yearly bool
+}
+
+// ErrICalendarInvalid means the data is not an iCalendar file
+var ErrICalendarInvalid = errors.New("invalid iCalendar data")
+
+// ParseICalendar reads the VEVENT entries of an iCalendar (RFC 5545) file. Only the date part of
+// DTSTART and DTEND is used, events without a valid DTSTART are skipped.
+func ParseICalendar(r io.Reader) ([]ICalEvent, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return nil, ErrICalendarInvalid
+	}
+
+	var events []ICalEvent
+	var current *ICalEvent
+	for _, line := range lines {
+		name, params, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &ICalEvent{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if current != nil && !current.Start.IsZero() {
+				if current.End.IsZero() || !current.End.After(current.Start) {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeICalText(value)
+		case name == "DTSTART":
+			current.Start, _ = parseICalDate(value)
+		case name == "DTEND":
+			end, err := parseICalDate(value)
+			// DTEND of a timed event is within the last day unless it is at midnight, of an all-day event
+			// the day after
+			if err == nil && !strings.Contains(strings.ToUpper(params), "VALUE=DATE") && icalTimeAfterMidnight(value) {
+				end = end.AddDate(0, 0, 1)
+			}
+			current.End = end
+		case name == "RRULE":
+			current.Yearly = strings.Contains(strings.ToUpper(value), "FREQ=YEARLY")
+		}
+	}
+	return events, nil
+}
+
+// unfoldICalLines joins folded lines, continuation lines start with a space or a tab
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitICalLine splits "NAME;PARAMS:VALUE" into its parts, the name is upper case
+func splitICalLine(line string) (name, params, value string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), "", ""
+	}
+	name, value = line[:colon], line[colon+1:]
+	if semicolon := strings.Index(name, ";"); semicolon >= 0 {
+		name, params = name[:semicolon], name[semicolon+1:]
+	}
+	return strings.ToUpper(name), params, value
+}
+
+func parseICalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, ErrICalendarInvalid
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// icalTimeAfterMidnight reports whether a DATE-TIME value like 20261019T083000 has a time after 00:00
+func icalTimeAfterMidnight(value string) bool {
+	if len(value) <= 9 {
+		return false
+	}
+	return strings.Trim(strings.TrimSuffix(strings.ToUpper(value[9:]), "Z"), "0") != ""
+}
+
+func unescapeICalText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// ICalFeedEvent is a timed event written to an iCalendar feed. Start and End are written as floating
+// local times, the calendar app shows them as they are in its own time zone.
+type ICalFeedEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Modified    time.Time
+}
+
+// WriteICalendar writes the events as an iCalendar (RFC 5545) file named name
+func WriteICalendar(w io.Writer, name string, events []ICalFeedEvent) error {
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//HotelQu//Schedule Feed//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + escapeICalText(name),
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+event.Start.Format("20060102T150405"),
+			"DTEND:"+event.End.Format("20060102T150405"),
+			"SUMMARY:"+escapeICalText(event.Summary),
+		)
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if !event.Modified.IsZero() {
+			lines = append(lines, "LAST-MODIFIED:"+event.Modified.UTC().Format("20060102T150405Z"))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICalLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func escapeICalText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// foldICalLine splits lines longer than 75 octets without cutting a UTF-8 character
+func foldICalLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestParseICalendarEventDays(t *testing.T) {
+	cases := []struct {
+		name       string
+		start, end string
+		// wantEnd is the exclusive end date of the holiday
+		wantEnd string
+	}{
+		{"all-day event", "DTSTART;VALUE=DATE:20261018", "DTEND;VALUE=DATE:20261019", "2026-10-19"},
+		{"all-day event without end", "DTSTART;VALUE=DATE:20261018", "", "2026-10-19"},
+		{"timed event ending at midnight", "DTSTART:20261018T000000", "DTEND:20261019T000000", "2026-10-19"},
+		{"timed event ending at midnight UTC", "DTSTART:20261018T000000Z", "DTEND:20261019T000000Z", "2026-10-19"},
+		{"timed event ending the next morning", "DTSTART:20261018T080000", "DTEND:20261019T083000", "2026-10-20"},
+		{"timed event within the day", "DTSTART:20261018T080000", "DTEND:20261018T170000", "2026-10-19"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := []string{"BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:1", "SUMMARY:Holiday", tc.start}
+			if tc.end != "" {
+				lines = append(lines, tc.end)
+			}
+			lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+			events, err := ParseICalendar(strings.NewReader(strings.Join(lines, "\r\n")))
+			if err != nil {
+				t.Fatalf("ParseICalendar: %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			if start := events[0].Start.Format("2006-01-02"); start != "2026-10-18" {
+				t.Errorf("start = %s, want 2026-10-18", start)
+			}
+			if end := events[0].End.Format("2006-01-02"); end != tc.wantEnd {
+				t.Errorf("end = %s, want %s", end, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestWriteICalendarEvent(t *testing.T) {
+	start := time.Date(2026, 10, 18, 22, 0, 0, 0, time.Local)
+	cases := []struct {
+		name  string
+		event ICalFeedEvent
+		want  []string
+	}{
+		{"overnight shift", ICalFeedEvent{UID: "schedule-1@hotelqu", Summary: "Night", Start: start, End: start.Add(8 * time.Hour)},
+			[]string{"UID:schedule-1@hotelqu", "DTSTART:20261018T220000", "DTEND:20261019T060000", "SUMMARY:Night"}},
+		{"escaped text", ICalFeedEvent{UID: "2", Summary: `Morning; Lobby, Desk\1`, Description: "Line 1\nLine 2", Start: start, End: start},
+			[]string{`SUMMARY:Morning\; Lobby\, Desk\\1`, `DESCRIPTION:Line 1\nLine 2`}},
+		{"modified time in UTC", ICalFeedEvent{UID: "3", Summary: "Evening", Start: start, End: start,
+			Modified: time.Date(2026, 10, 1, 9, 30, 0, 0, time.FixedZone("WIB", 7*3600))},
+			[]string{"LAST-MODIFIED:20261001T023000Z"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := WriteICalendar(&out, "Shifts, HotelQu", []ICalFeedEvent{tc.event}); err != nil {
+				t.Fatalf("WriteICalendar: %v", err)
+			}
+			body := out.String()
+			if !strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(body, "END:VCALENDAR\r\n") {
+				t.Errorf("calendar is not wrapped in VCALENDAR with CRLF line ends:\n%s", body)
+			}
+			if !strings.Contains(body, "X-WR-CALNAME:Shifts\\, HotelQu\r\n") {
+				t.Errorf("calendar name is not escaped:\n%s", body)
+			}
+			lines := strings.Split(body, "\r\n")
+			for _, want := range tc.want {
+				found := false
+				for _, line := range lines {
+					found = found || line == want
+				}
+				if !found {
+					t.Errorf("missing line %q in:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteICalendarFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("Shift ", 10) + strings.Repeat("é", 40)
+	var out bytes.Buffer
+	if err := WriteICalendar(&out, "Shifts", []ICalFeedEvent{{UID: "1", Summary: summary}}); err != nil {
+		t.Fatalf("WriteICalendar: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line cuts a character: %q", line)
+		}
+	}
+	if unfolded := strings.ReplaceAll(out.String(), "\r\n ", ""); !strings.Contains(unfolded, "SUMMARY:"+summary+"\r\n") {
+		t.Errorf("unfolded output does not contain the summary:\n%s", out.String())
+	}
+}
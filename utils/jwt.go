@@ -1,59 +1,80 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
 	"github.com/OrryFrasetyo/go-api-hotelqu/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// secret key to sign jwt, in production use environment variable
-var jwtKey = []byte("hotelqu_secret_key") //hotel_qu_secret_key
+// secret key to sign jwt, taken from JWT_SECRET / jwt.secret
+func jwtKey() []byte {
+	return []byte(config.App.JWT.Secret)
+}
 
 type JWTClaims struct {
-	Id    int    `json:"id"`
-	Email string `json:"email"`
+	Id      int    `json:"id"`
+	Email   string `json:"email"`
+	Version int    `json:"ver"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken creates JWT tokens for employees
-func GenerateToken(employee models.Employee) (string, error) {
-	// Set token expiration time (24 hours)
-	expirationTime := time.Now().Add(24 * time.Hour)
-	
+// GenerateToken creates JWT access tokens for employees
+func GenerateToken(employee models.Employee) (string, time.Time, error) {
+	// Set token expiration time (jwt.token_ttl, default 15 minutes)
+	expirationTime := time.Now().Add(config.App.JWT.TokenTTL.Duration)
+
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
 	claims := &JWTClaims{
-		Id:    employee.Id,
-		Email: employee.Email,
+		Id:      employee.Id,
+		Email:   employee.Email,
+		Version: employee.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := token.SignedString(jwtKey())
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
-	
-	return tokenString, nil
+
+	return tokenString, expirationTime, nil
 }
 
 func ValidateToken(tokenString string) (*JWTClaims, error) {
 	claims := &JWTClaims{}
-	
+
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
-	})
-	
+		return jwtKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if !token.Valid {
 		return nil, errors.New("invalid token")
 	}
-	
+
 	return claims, nil
-}
\ No newline at end of file
+}
+
+func randomHex(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
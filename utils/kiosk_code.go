@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrKioskCodeInvalid = errors.New("invalid kiosk code")
+
+// NewKioskSecret returns a random secret to sign kiosk codes with
+func NewKioskSecret() (string, error) {
+	return randomHex(32)
+}
+
+// NewKioskToken returns a random device token a kiosk authenticates with
+func NewKioskToken() (string, error) {
+	return randomHex(32)
+}
+
+// KioskCounter returns the number of the code period t falls in
+func KioskCounter(t time.Time, period time.Duration) int64 {
+	return t.Unix() / int64(period/time.Second)
+}
+
+// KioskCode returns the code a kiosk shows during the period counter, written as K<kiosk>-<counter>-<signature>
+// so the QR code tells which kiosk it belongs to
+func KioskCode(kioskID uint, counter int64, secret string) string {
+	return fmt.Sprintf("K%d-%d-%s", kioskID, counter, kioskSignature(kioskID, counter, secret))
+}
+
+// ParseKioskCode splits a code into the kiosk id and the period, the signature is checked by VerifyKioskCode
+func ParseKioskCode(code string) (uint, int64, error) {
+	parts := strings.Split(strings.TrimSpace(code), "-")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "K") {
+		return 0, 0, ErrKioskCodeInvalid
+	}
+	kioskID, err := strconv.ParseUint(parts[0][1:], 10, 64)
+	if err != nil {
+		return 0, 0, ErrKioskCodeInvalid
+	}
+	counter, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, ErrKioskCodeInvalid
+	}
+	return uint(kioskID), counter, nil
+}
+
+// VerifyKioskCode reports whether the code was signed with the secret of the kiosk
+func VerifyKioskCode(code string, secret string) bool {
+	kioskID, counter, err := ParseKioskCode(code)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(strings.TrimSpace(code)), []byte(KioskCode(kioskID, counter, secret)))
+}
+
+func kioskSignature(kioskID uint, counter int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d:%d", kioskID, counter)
+	return hex.EncodeToString(mac.Sum(nil))[:20]
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"gorm.io/gorm"
+)
+
+// lockCountResetAfter is how long an account or ip must stay without failures before the backoff starts over
+const lockCountResetAfter = 24 * time.Hour
+
+// NormalizeLoginEmail returns the key used to throttle logins for an email
+func NormalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CheckLoginLock returns the running lockout of the email or ip, nil when the login may proceed
+func CheckLoginLock(email, ip string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := models.DB.
+		Where("((kind = ? AND identifier = ?) OR (kind = ? AND identifier = ?)) AND locked_until > ?",
+			models.LoginThrottleAccount, email, models.LoginThrottleIP, ip, time.Now()).
+		Order("locked_until DESC").
+		First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordLoginFailure counts a failed login for the email and ip, locks them once the limit is
+// reached and writes an audit entry for every lockout. The longest lockout started is returned.
+func RecordLoginFailure(email, ip string, employeeID *int) (*models.LoginThrottle, error) {
+	cfg := config.App.Login
+
+	var started *models.LoginThrottle
+	for _, target := range []struct {
+		kind, key   string
+		maxAttempts int
+	}{
+		{models.LoginThrottleAccount, email, cfg.MaxAttempts},
+		{models.LoginThrottleIP, ip, cfg.IPMaxAttempts},
+	} {
+		if target.key == "" {
+			continue
+		}
+
+		throttle, err := registerLoginFailure(target.kind, target.key, target.maxAttempts)
+		if err != nil {
+			return nil, err
+		}
+		if throttle == nil {
+			continue
+		}
+
+		entry := models.AuditLog{
+			Action:    models.AuditLoginLockout,
+			IPAddress: Truncate(ip, 45),
+			Detail: fmt.Sprintf("%s %s locked until %s after %d failed attempts (lockout %d)",
+				target.kind, target.key, throttle.LockedUntil.Format(time.RFC3339), target.maxAttempts, throttle.LockCount),
+		}
+		if target.kind == models.LoginThrottleAccount {
+			entry.EmployeeID = employeeID
+		}
+		if err := models.RecordAudit(models.DB, entry); err != nil {
+			log.Printf("ERROR: failed to write audit entry: %v", err)
+		}
+
+		if started == nil || throttle.LockedUntil.After(*started.LockedUntil) {
+			started = throttle
+		}
+	}
+	return started, nil
+}
+
+// registerLoginFailure increments one counter. Updates only apply when the row is unchanged since it
+// was read, so concurrent failures on several instances are all counted.
+func registerLoginFailure(kind, key string, maxAttempts int) (*models.LoginThrottle, error) {
+	cfg := config.App.Login
+
+	for attempt := 0; attempt < 5; attempt++ {
+		var throttle models.LoginThrottle
+		if err := models.DB.Where(models.LoginThrottle{Kind: kind, Identifier: key}).FirstOrCreate(&throttle).Error; err != nil {
+			// another instance may have created the row in the meantime
+			if err := models.DB.Where(models.LoginThrottle{Kind: kind, Identifier: key}).First(&throttle).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		now := time.Now()
+		failures := throttle.Failures + 1
+		lockCount := throttle.LockCount
+		if throttle.LastFailedAt == nil || throttle.LastFailedAt.Before(now.Add(-cfg.AttemptWindow.Duration)) {
+			failures = 1
+		}
+		if throttle.LastFailedAt == nil || throttle.LastFailedAt.Before(now.Add(-lockCountResetAfter)) {
+			lockCount = 0
+		}
+
+		updates := map[string]interface{}{
+			"failures":       failures,
+			"lock_count":     lockCount,
+			"last_failed_at": now,
+		}
+		locked := failures >= maxAttempts
+		if locked {
+			lockCount++
+			lockedUntil := now.Add(lockoutDuration(lockCount))
+			updates["failures"] = 0
+			updates["lock_count"] = lockCount
+			updates["locked_until"] = lockedUntil
+			throttle.LockedUntil = &lockedUntil
+		}
+
+		result := models.DB.Model(&models.LoginThrottle{}).
+			Where("id = ? AND failures = ? AND lock_count = ?", throttle.ID, throttle.Failures, throttle.LockCount).
+			Updates(updates)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		if !locked {
+			return nil, nil
+		}
+		throttle.Failures = 0
+		throttle.LockCount = lockCount
+		throttle.LastFailedAt = &now
+		return &throttle, nil
+	}
+	return nil, errors.New("login throttle is being updated concurrently")
+}
+
+// lockoutDuration doubles the base lockout for every lockout after the first, capped at the configured maximum
+func lockoutDuration(lockCount int) time.Duration {
+	cfg := config.App.Login
+	duration := cfg.LockoutBase.Duration
+	for i := 1; i < lockCount && duration < cfg.LockoutMax.Duration; i++ {
+		duration *= 2
+	}
+	if duration > cfg.LockoutMax.Duration {
+		duration = cfg.LockoutMax.Duration
+	}
+	return duration
+}
+
+// RecordLoginSuccess clears the failed attempts of the account. The ip counter is kept so one valid
+// account cannot be used to keep guessing others from the same address.
+func RecordLoginSuccess(email string) error {
+	return models.DB.Where("kind = ? AND identifier = ?", models.LoginThrottleAccount, email).
+		Delete(&models.LoginThrottle{}).Error
+}
+
+// UnlockLogin removes a lockout and its counters and records who lifted it
+func UnlockLogin(throttle models.LoginThrottle, actorID int, employeeID *int) error {
+	return models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&throttle).Error; err != nil {
+			return err
+		}
+		return models.RecordAudit(tx, models.AuditLog{
+			Action:     models.AuditLoginUnlock,
+			ActorID:    &actorID,
+			EmployeeID: employeeID,
+			Detail:     fmt.Sprintf("%s %s unlocked", throttle.Kind, throttle.Identifier),
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"gorm.io/gorm"
+)
+
+var ErrResetTokenInvalid = errors.New("invalid or expired reset token")
+
+// CreatePasswordResetToken invalidates older unused codes of the employee and returns a new one
+func CreatePasswordResetToken(employee models.Employee) (string, time.Time, error) {
+	plainToken, err := randomHex(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(config.App.Password.ResetTokenTTL.Duration)
+
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("employee_id = ? AND used_at IS NULL", employee.Id).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PasswordResetToken{
+			EmployeeID: employee.Id,
+			TokenHash:  HashToken(plainToken),
+			ExpiresAt:  expiresAt,
+		}).Error
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return plainToken, expiresAt, nil
+}
+
+// ResetPassword consumes the reset code, sets the new password and logs the employee out everywhere
+func ResetPassword(plainToken, newPassword string) error {
+	var employeeID int
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		var resetToken models.PasswordResetToken
+		if err := tx.Where("token_hash = ?", HashToken(plainToken)).First(&resetToken).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrResetTokenInvalid
+			}
+			return err
+		}
+		if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+			return ErrResetTokenInvalid
+		}
+
+		// mark as used first so the same code cannot be used twice concurrently
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrResetTokenInvalid
+		}
+
+		var employee models.Employee
+		if err := tx.First(&employee, resetToken.EmployeeID).Error; err != nil {
+			return err
+		}
+		if err := employee.HashPassword(newPassword); err != nil {
+			return err
+		}
+		employeeID = employee.Id
+		return tx.Model(&employee).Updates(map[string]interface{}{
+			"password":             employee.Password,
+			"must_change_password": false,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return RevokeAllSessions(employeeID)
+}
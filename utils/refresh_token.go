@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+	"github.com/OrryFrasetyo/go-api-hotelqu/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	// ErrRefreshTokenReused means an already rotated token was presented again, which suggests it was stolen
+	ErrRefreshTokenReused = errors.New("refresh token already used")
+	// ErrEmployeeInactive means the employee was deactivated or terminated after the token was issued
+	ErrEmployeeInactive = errors.New("employee is not active")
+)
+
+// TokenPair is returned on login and refresh
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// SessionInfo describes the client the refresh token is issued to
+type SessionInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// HashToken returns the hex sha256 of a token, used to store refresh tokens
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueTokenPair creates an access token and stores a new refresh token for the employee
+func IssueTokenPair(tx *gorm.DB, employee models.Employee, session SessionInfo) (TokenPair, *models.RefreshToken, error) {
+	accessToken, accessExpiresAt, err := GenerateToken(employee)
+	if err != nil {
+		return TokenPair{}, nil, err
+	}
+
+	plainRefreshToken, err := randomHex(32)
+	if err != nil {
+		return TokenPair{}, nil, err
+	}
+
+	refreshToken := models.RefreshToken{
+		EmployeeID: employee.Id,
+		TokenHash:  HashToken(plainRefreshToken),
+		ExpiresAt:  time.Now().Add(config.App.JWT.RefreshTokenTTL.Duration),
+		UserAgent:  Truncate(session.UserAgent, 255),
+		IPAddress:  Truncate(session.IPAddress, 45),
+	}
+	if err := tx.Create(&refreshToken).Error; err != nil {
+		return TokenPair{}, nil, err
+	}
+
+	return TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          plainRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, &refreshToken, nil
+}
+
+// RotateRefreshToken revokes the presented refresh token and issues a new pair.
+// Presenting a token that was already rotated revokes every session of the employee.
+func RotateRefreshToken(plainToken string, session SessionInfo) (TokenPair, error) {
+	var pair TokenPair
+	var reused bool
+	var reusedEmployeeID int
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Where("token_hash = ?", HashToken(plainToken)).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenInvalid
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused, reusedEmployeeID = true, current.EmployeeID
+			return ErrRefreshTokenReused
+		}
+		if time.Now().After(current.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		var employee models.Employee
+		if err := tx.First(&employee, current.EmployeeID).Error; err != nil {
+			return ErrRefreshTokenInvalid
+		}
+		if !employee.IsActiveOn(time.Now().Format("2006-01-02")) {
+			return ErrEmployeeInactive
+		}
+
+		newPair, newToken, err := IssueTokenPair(tx, employee, session)
+		if err != nil {
+			return err
+		}
+
+		// only one request may rotate the token, a concurrent rotation counts as reuse
+		now := time.Now()
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{"revoked_at": now, "replaced_by": newToken.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reused, reusedEmployeeID = true, current.EmployeeID
+			return ErrRefreshTokenReused
+		}
+
+		pair = newPair
+		return nil
+	})
+
+	if reused {
+		if revokeErr := RevokeAllSessions(reusedEmployeeID); revokeErr != nil {
+			return TokenPair{}, revokeErr
+		}
+	}
+	return pair, err
+}
+
+// RevokeRefreshToken revokes a single refresh token belonging to the employee
+func RevokeRefreshToken(employeeID int, plainToken string) error {
+	return models.DB.Model(&models.RefreshToken{}).
+		Where("employee_id = ? AND token_hash = ? AND revoked_at IS NULL", employeeID, HashToken(plainToken)).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAccessToken puts the access token id on the revocation list until it expires
+func RevokeAccessToken(claims *JWTClaims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	// drop entries of tokens that expired anyway
+	models.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+
+	return models.DB.Create(&models.RevokedToken{
+		JTI:        claims.ID,
+		EmployeeID: claims.Id,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	}).Error
+}
+
+// IsAccessTokenRevoked checks the revocation list for the token id
+func IsAccessTokenRevoked(jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	var count int64
+	err := models.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+// RevokeAllSessions logs the employee out of every device: all refresh tokens are revoked and
+// the token version is incremented so access tokens issued before are rejected by JWTAuth
+func RevokeAllSessions(employeeID int) error {
+	return models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("employee_id = ? AND revoked_at IS NULL", employeeID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Employee{}).
+			Where("id = ?", employeeID).
+			Update("token_version", gorm.Expr("token_version + 1")).Error
+	})
+}
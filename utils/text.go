@@ -0,0 +1,13 @@
+package utils
+
+// Truncate shortens value to at most max characters without splitting a multi-byte character
+func Truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	runes := []rune(value)
+	if len(runes) > max {
+		runes = runes[:max]
+	}
+	return string(runes)
+}
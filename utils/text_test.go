@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		value string
+		max   int
+		want  string
+	}{
+		{"Nyepi", 10, "Nyepi"},
+		{"Hari Raya", 4, "Hari"},
+		{"Tahun Baru Imlek 春节", 18, "Tahun Baru Imlek 春"},
+		{"春节春节", 3, "春节春"},
+	}
+	for _, tc := range cases {
+		if got := Truncate(tc.value, tc.max); got != tc.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tc.value, tc.max, got, tc.want)
+		}
+	}
+}
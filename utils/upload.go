@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/OrryFrasetyo/go-api-hotelqu/config"
+)
+
+var (
+	ErrUploadTooLarge = errors.New("uploaded file is too large")
+	ErrUploadType     = errors.New("only JPG, JPEG, and PNG files are allowed")
+)
+
+// SaveImage checks that the upload is a JPG or PNG within the size limit and stores it in dir of the upload
+// directory as <prefix>_<random><ext>. It returns the URL path the image is served at.
+func SaveImage(file *multipart.FileHeader, dir, prefix string) (string, error) {
+	if file.Size > config.App.Upload.MaxSizeBytes() {
+		return "", ErrUploadTooLarge
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		return "", ErrUploadType
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	// the content has to match, not only the file name
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", ErrUploadType
+	}
+	if contentType := http.DetectContentType(head[:n]); contentType != "image/jpeg" && contentType != "image/png" {
+		return "", ErrUploadType
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	uploadDir := filepath.Join(config.App.Upload.Dir, dir)
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return "", fmt.Errorf("create upload directory: %w", err)
+	}
+	suffix, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	filename := prefix + "_" + suffix + ext
+
+	dst, err := os.Create(filepath.Join(uploadDir, filename))
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return path.Join("/uploads", filepath.ToSlash(dir), filename), nil
+}
+
+// RemoveUpload deletes a file stored by SaveImage given its URL path
+func RemoveUpload(urlPath string) {
+	relative := strings.TrimPrefix(urlPath, "/uploads/")
+	if relative == urlPath || strings.Contains(relative, "..") {
+		return
+	}
+	os.Remove(filepath.Join(config.App.Upload.Dir, filepath.FromSlash(relative)))
+}